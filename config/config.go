@@ -0,0 +1,382 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	ghconfig "github.com/cli/go-gh/v2/pkg/config"
+	"github.com/kosuke9809/gh-review/git"
+	"github.com/kosuke9809/gh-review/highlight"
+	"github.com/kosuke9809/gh-review/model"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the user configuration read from config.yml.
+type Config struct {
+	Filters  []Filter        `yaml:"filters"`
+	Priority Priority        `yaml:"priority"`
+	SLA      SLA             `yaml:"sla"`
+	Worktree Worktree        `yaml:"worktree"`
+	Diff     Diff            `yaml:"diff"`
+	Repos    map[string]Repo `yaml:"repos"` // keyed by "owner/repo"
+}
+
+// Repo holds settings that apply to a single repository.
+type Repo struct {
+	Hooks    Hooks             `yaml:"hooks"`
+	Commands map[string]string `yaml:"commands"` // presets for the Run screen, e.g. test: go test ./...
+	Coverage string            `yaml:"coverage"` // profile in the worktree; cover.out, lcov.info, ... when empty
+}
+
+// CommandNames returns the names of the command presets in sorted order.
+func (r Repo) CommandNames() []string {
+	return slices.Sorted(maps.Keys(r.Commands))
+}
+
+// Hooks are shell commands run inside a PR worktree, in order, stopping at
+// the first failure. They see PR_NUMBER, PR_HEAD_REF, PR_BASE_REF,
+// PR_HEAD_SHA, PR_AUTHOR, PR_WORKTREE and REPO_ROOT.
+type Hooks struct {
+	PostCreate []string `yaml:"post_create"`
+	PostUpdate []string `yaml:"post_update"`
+}
+
+// Worktree configures where PR worktrees are created. Name is a template
+// with {owner}, {repo}, {number}, {headRef} and {author}; it must contain
+// {number}.
+type Worktree struct {
+	Dir  string `yaml:"dir"`  // absolute, "~/...", or relative to the repository root
+	Name string `yaml:"name"` // e.g. "{owner}/{repo}/{number}-{headRef}"
+}
+
+// Diff configures how the Diff tab computes diffs. With Local, the diff is
+// computed with `git diff base...head` when the PR's commits are available
+// locally, instead of using the patches from the API.
+//
+// Pager is a shell command that formats each file's patch, read on stdin,
+// e.g. "delta --width=$COLUMNS". Difftool is a shell command that shows the
+// whole PR diff between $BASE and $HEAD; git difftool is used when empty.
+// Syntax is the chroma style used to highlight code, or "none". Split shows
+// diffs side by side when the terminal is wide enough.
+type Diff struct {
+	Local            bool   `yaml:"local"`
+	Context          int    `yaml:"context"` // lines of context in local diffs
+	IgnoreWhitespace bool   `yaml:"ignore_whitespace"`
+	Renames          bool   `yaml:"renames"`
+	Pager            string `yaml:"pager"`
+	Difftool         string `yaml:"difftool"`
+	Syntax           string `yaml:"syntax"`
+	Split            bool   `yaml:"split"`
+}
+
+// SLA configures review-age highlighting. Warn and Overdue count working
+// time only, as defined by WorkHours, WorkDays and Timezone.
+type SLA struct {
+	Warn      Duration `yaml:"warn"`
+	Overdue   Duration `yaml:"overdue"`
+	WorkHours string   `yaml:"work_hours"` // "09:00-18:00"
+	WorkDays  []string `yaml:"work_days"`  // "mon".."sun"
+	Timezone  string   `yaml:"timezone"`   // IANA name; empty means local
+}
+
+// Priority holds the weights of the review-queue priority score.
+// Fields left out of the file keep their defaults.
+type Priority struct {
+	WaitPerDay float64            `yaml:"wait_per_day"`
+	Blocking   float64            `yaml:"blocking"`
+	CIPass     float64            `yaml:"ci_pass"`
+	CIFail     float64            `yaml:"ci_fail"`
+	SmallDiff  float64            `yaml:"small_diff"`
+	DiffScale  int                `yaml:"diff_scale"`
+	ReReview   float64            `yaml:"re_review"`
+	Labels     map[string]float64 `yaml:"labels"`
+
+	RequiredApprovals int `yaml:"required_approvals"` // approvals a PR needs to merge
+}
+
+// Default returns the configuration used when no config file exists.
+func Default() Config {
+	w := model.DefaultPriorityWeights()
+	sla := model.DefaultSLA()
+	layout := git.DefaultLayout()
+	return Config{
+		Priority: Priority{
+			WaitPerDay: w.WaitPerDay,
+			Blocking:   w.Blocking,
+			CIPass:     w.CIPass,
+			CIFail:     w.CIFail,
+			SmallDiff:  w.SmallDiff,
+			DiffScale:  w.DiffScale,
+			ReReview:   w.ReReview,
+			Labels:     w.Labels,
+
+			RequiredApprovals: w.RequiredApprovals,
+		},
+		SLA: SLA{
+			Warn:      Duration(sla.Warn),
+			Overdue:   Duration(sla.Overdue),
+			WorkHours: "09:00-18:00",
+			WorkDays:  []string{"mon", "tue", "wed", "thu", "fri"},
+		},
+		Worktree: Worktree{Dir: layout.Dir, Name: layout.Name},
+		Diff:     Diff{Local: true, Context: 3, Renames: true, Syntax: "monokai"},
+	}
+}
+
+// Filter is a named PR filter appended to the built-in `f` cycle.
+type Filter struct {
+	Name            string   `yaml:"name"`
+	Authors         []string `yaml:"authors"`          // "@me" means the current user
+	Base            string   `yaml:"base"`             // glob, e.g. "release/*"
+	Head            string   `yaml:"head"`             // glob
+	Labels          []string `yaml:"labels"`           // all must be present
+	ReviewRequested bool     `yaml:"review_requested"` // only PRs requesting my review
+	OlderThan       Duration `yaml:"older_than"`       // waiting longer than this
+	Sizes           []string `yaml:"sizes"`            // any of XS, S, M, L, XL, XXL
+}
+
+// Duration is a time.Duration that also accepts a day suffix ("2d").
+type Duration time.Duration
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	v, err := ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// ParseDuration parses a Go duration string, additionally accepting whole
+// days such as "2d".
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// DefaultPath returns the config file location under gh's config directory.
+func DefaultPath() string {
+	return filepath.Join(ghconfig.ConfigDir(), "gh-review", "config.yml")
+}
+
+// Load reads and validates the config file, layered over Default.
+// A missing file yields Default.
+func Load(file string) (Config, error) {
+	cfg := Default()
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("read config %s: %w", file, err)
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse config %s: %w", file, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", file, err)
+	}
+	return cfg, nil
+}
+
+func (c Config) validate() error {
+	for i, f := range c.Filters {
+		if f.Name == "" {
+			return fmt.Errorf("filters[%d]: name is required", i)
+		}
+		for _, glob := range []string{f.Base, f.Head} {
+			if _, err := path.Match(glob, ""); err != nil {
+				return fmt.Errorf("filter %q: invalid pattern %q", f.Name, glob)
+			}
+		}
+	}
+	for _, f := range c.Filters {
+		for _, sz := range f.Sizes {
+			if !validSize(sz) {
+				return fmt.Errorf("filter %q: unknown size %q", f.Name, sz)
+			}
+		}
+	}
+	if c.Priority.DiffScale < 0 {
+		return fmt.Errorf("priority.diff_scale must not be negative")
+	}
+	if c.Priority.RequiredApprovals < 0 {
+		return fmt.Errorf("priority.required_approvals must not be negative")
+	}
+	if _, err := c.SLA.model(); err != nil {
+		return fmt.Errorf("sla: %w", err)
+	}
+	if err := c.WorktreeLayout().Validate(); err != nil {
+		return fmt.Errorf("worktree: %w", err)
+	}
+	if c.Diff.Context < 0 {
+		return fmt.Errorf("diff.context must not be negative")
+	}
+	if c.Diff.Syntax != highlight.None {
+		if _, err := highlight.New(c.Diff.Syntax); err != nil {
+			return fmt.Errorf("diff.syntax: %w", err)
+		}
+	}
+	for name, r := range c.Repos {
+		for cmd, script := range r.Commands {
+			if strings.TrimSpace(script) == "" {
+				return fmt.Errorf("repos.%s.commands.%s: command is empty", name, cmd)
+			}
+		}
+	}
+	return nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+func (s SLA) model() (model.SLA, error) {
+	sla := model.SLA{Warn: time.Duration(s.Warn), Overdue: time.Duration(s.Overdue)}
+	start, end, ok := strings.Cut(s.WorkHours, "-")
+	if !ok {
+		return sla, fmt.Errorf("invalid work_hours %q, want HH:MM-HH:MM", s.WorkHours)
+	}
+	var err error
+	if sla.Schedule.Start, err = parseClock(start); err != nil {
+		return sla, err
+	}
+	if sla.Schedule.End, err = parseClock(end); err != nil {
+		return sla, err
+	}
+	if sla.Schedule.End <= sla.Schedule.Start {
+		return sla, fmt.Errorf("work_hours %q ends before it starts", s.WorkHours)
+	}
+	for _, d := range s.WorkDays {
+		wd, ok := weekdays[strings.ToLower(d)]
+		if !ok {
+			return sla, fmt.Errorf("unknown work day %q", d)
+		}
+		sla.Schedule.Days[wd] = true
+	}
+	sla.Schedule.Location = time.Local
+	if s.Timezone != "" {
+		if sla.Schedule.Location, err = time.LoadLocation(s.Timezone); err != nil {
+			return sla, fmt.Errorf("unknown timezone %q", s.Timezone)
+		}
+	}
+	return sla, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// ReviewSLA converts the sla section to its model form.
+func (c Config) ReviewSLA() model.SLA {
+	sla, err := c.SLA.model()
+	if err != nil {
+		return model.DefaultSLA()
+	}
+	return sla
+}
+
+// Repo returns the settings for the repository named "owner/repo", matched
+// case-insensitively.
+func (c Config) Repo(name string) Repo {
+	for key, r := range c.Repos {
+		if strings.EqualFold(key, name) {
+			return r
+		}
+	}
+	return Repo{}
+}
+
+// DiffOptions converts the diff section to the options used by git.
+func (c Config) DiffOptions() git.DiffOptions {
+	return git.DiffOptions{Context: c.Diff.Context, IgnoreWhitespace: c.Diff.IgnoreWhitespace, Renames: c.Diff.Renames}
+}
+
+// Highlighter returns the syntax highlighter of the diff section, or nil when
+// highlighting is off.
+func (c Config) Highlighter() *highlight.Highlighter {
+	if c.Diff.Syntax == highlight.None {
+		return nil
+	}
+	h, _ := highlight.New(c.Diff.Syntax) // checked by validate
+	return h
+}
+
+// WorktreeLayout converts the worktree section to the layout used by git.
+func (c Config) WorktreeLayout() git.Layout {
+	return git.Layout{Dir: c.Worktree.Dir, Name: c.Worktree.Name}
+}
+
+// PriorityWeights converts the priority section to its model form.
+func (c Config) PriorityWeights() model.PriorityWeights {
+	p := c.Priority
+	return model.PriorityWeights{
+		WaitPerDay: p.WaitPerDay,
+		Blocking:   p.Blocking,
+		CIPass:     p.CIPass,
+		CIFail:     p.CIFail,
+		SmallDiff:  p.SmallDiff,
+		DiffScale:  p.DiffScale,
+		ReReview:   p.ReReview,
+		Labels:     p.Labels,
+
+		RequiredApprovals: p.RequiredApprovals,
+	}
+}
+
+func validSize(s string) bool {
+	switch model.SizeClass(strings.ToUpper(s)) {
+	case model.SizeXS, model.SizeS, model.SizeM, model.SizeL, model.SizeXL, model.SizeXXL:
+		return true
+	}
+	return false
+}
+
+// CustomFilters converts the configured filters to their model form.
+func (c Config) CustomFilters() []model.CustomFilter {
+	result := make([]model.CustomFilter, 0, len(c.Filters))
+	for _, f := range c.Filters {
+		var sizes []model.SizeClass
+		for _, sz := range f.Sizes {
+			sizes = append(sizes, model.SizeClass(strings.ToUpper(sz)))
+		}
+		result = append(result, model.CustomFilter{
+			Name:            f.Name,
+			Authors:         f.Authors,
+			Base:            f.Base,
+			Head:            f.Head,
+			Labels:          f.Labels,
+			ReviewRequested: f.ReviewRequested,
+			OlderThan:       time.Duration(f.OlderThan),
+			Sizes:           sizes,
+		})
+	}
+	return result
+}
@@ -0,0 +1,257 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/config"
+	"github.com/kosuke9809/gh-review/git"
+)
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(file, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoad_Missing(t *testing.T) {
+	cfg, err := config.Load(filepath.Join(t.TempDir(), "config.yml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Filters) != 0 {
+		t.Errorf("Filters = %v, want none", cfg.Filters)
+	}
+}
+
+func TestLoad_Filters(t *testing.T) {
+	file := writeConfig(t, `
+filters:
+  - name: Team release
+    authors: ["@me", alice]
+    base: "release/*"
+  - name: Waiting on me
+    review_requested: true
+    older_than: 2d
+    sizes: [xs, S]
+`)
+	cfg, err := config.Load(file)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	filters := cfg.CustomFilters()
+	if len(filters) != 2 {
+		t.Fatalf("CustomFilters() len = %d, want 2", len(filters))
+	}
+	if filters[0].Base != "release/*" || len(filters[0].Authors) != 2 {
+		t.Errorf("filters[0] = %+v", filters[0])
+	}
+	if !filters[1].ReviewRequested || filters[1].OlderThan != 48*time.Hour || len(filters[1].Sizes) != 2 || filters[1].Sizes[0] != "XS" {
+		t.Errorf("filters[1] = %+v", filters[1])
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing name", "filters:\n  - base: main\n"},
+		{"bad glob", "filters:\n  - name: x\n    base: \"[\"\n"},
+		{"bad duration", "filters:\n  - name: x\n    older_than: soon\n"},
+		{"bad size", "filters:\n  - name: x\n    sizes: [huge]\n"},
+		{"bad work hours", "sla:\n  work_hours: \"18:00-09:00\"\n"},
+		{"bad work day", "sla:\n  work_days: [someday]\n"},
+		{"worktree name without number", "worktree:\n  name: \"{headRef}\"\n"},
+		{"unknown worktree placeholder", "worktree:\n  name: \"{number}-{title}\"\n"},
+		{"negative diff context", "diff:\n  context: -1\n"},
+		{"unknown syntax style", "diff:\n  syntax: nope\n"},
+		{"empty command", "repos:\n  octo/app:\n    commands:\n      test: \"\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := config.Load(writeConfig(t, tt.body)); err == nil {
+				t.Error("Load() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"3d", 72 * time.Hour},
+		{"90m", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := config.ParseDuration(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestLoad_PriorityOverridesDefaults(t *testing.T) {
+	file := writeConfig(t, `
+priority:
+  blocking: 25
+  required_approvals: 2
+  labels:
+    hotfix: 30
+`)
+	cfg, err := config.Load(file)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	w := cfg.PriorityWeights()
+	def := config.Default().PriorityWeights()
+	if w.Blocking != 25 {
+		t.Errorf("Blocking = %v, want 25", w.Blocking)
+	}
+	if w.RequiredApprovals != 2 {
+		t.Errorf("RequiredApprovals = %v, want 2", w.RequiredApprovals)
+	}
+	if w.WaitPerDay != def.WaitPerDay {
+		t.Errorf("WaitPerDay = %v, want default %v", w.WaitPerDay, def.WaitPerDay)
+	}
+	if w.Labels["hotfix"] != 30 || w.Labels["urgent"] != def.Labels["urgent"] {
+		t.Errorf("Labels = %v, want hotfix added to defaults", w.Labels)
+	}
+}
+
+func TestLoad_SLA(t *testing.T) {
+	file := writeConfig(t, `
+sla:
+  warn: 2h
+  overdue: 6h
+  work_hours: "10:00-16:30"
+  work_days: [Mon, Wed]
+  timezone: Asia/Tokyo
+`)
+	cfg, err := config.Load(file)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	sla := cfg.ReviewSLA()
+	if sla.Warn != 2*time.Hour || sla.Overdue != 6*time.Hour {
+		t.Errorf("thresholds = %v/%v, want 2h/6h", sla.Warn, sla.Overdue)
+	}
+	if sla.Schedule.Start != 10*time.Hour || sla.Schedule.End != 16*time.Hour+30*time.Minute {
+		t.Errorf("work hours = %v-%v", sla.Schedule.Start, sla.Schedule.End)
+	}
+	if !sla.Schedule.Days[time.Monday] || sla.Schedule.Days[time.Tuesday] {
+		t.Errorf("work days = %v", sla.Schedule.Days)
+	}
+	if sla.Schedule.Location.String() != "Asia/Tokyo" {
+		t.Errorf("location = %v", sla.Schedule.Location)
+	}
+}
+
+func TestDefault_SLAIsOneBusinessDay(t *testing.T) {
+	sla := config.Default().ReviewSLA()
+	if sla.Overdue != 9*time.Hour || !sla.Schedule.Days[time.Friday] || sla.Schedule.Days[time.Saturday] {
+		t.Errorf("default SLA = %+v", sla)
+	}
+}
+
+func TestLoad_Worktree(t *testing.T) {
+	file := writeConfig(t, `
+worktree:
+  dir: /wt
+  name: "{owner}/{repo}/{number}-{headRef}"
+`)
+	cfg, err := config.Load(file)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got := cfg.WorktreeLayout().Path("/repo", git.LayoutVars{Owner: "octo", Repo: "app", Number: 5, HeadRef: "fix"})
+	if got != "/wt/octo/app/5-fix" {
+		t.Errorf("worktree path = %q", got)
+	}
+	if def := config.Default().WorktreeLayout(); def != git.DefaultLayout() {
+		t.Errorf("default layout = %+v, want %+v", def, git.DefaultLayout())
+	}
+}
+
+func TestLoad_RepoHooks(t *testing.T) {
+	file := writeConfig(t, `
+repos:
+  Octo/App:
+    hooks:
+      post_create: ["npm ci", "cp \"$REPO_ROOT/.env.local\" ."]
+      post_update: ["npm ci"]
+`)
+	cfg, err := config.Load(file)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	hooks := cfg.Repo("octo/app").Hooks
+	if len(hooks.PostCreate) != 2 || hooks.PostCreate[1] != `cp "$REPO_ROOT/.env.local" .` || len(hooks.PostUpdate) != 1 {
+		t.Errorf("Repo(octo/app).Hooks = %+v", hooks)
+	}
+	if other := cfg.Repo("octo/other"); len(other.Hooks.PostCreate) != 0 {
+		t.Errorf("Repo(octo/other) = %+v, want empty", other)
+	}
+}
+
+func TestLoad_RepoCommands(t *testing.T) {
+	file := writeConfig(t, `
+repos:
+  octo/app:
+    commands:
+      test: go test ./...
+      lint: golangci-lint run
+      build: go build ./...
+`)
+	cfg, err := config.Load(file)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	r := cfg.Repo("octo/app")
+	if got := strings.Join(r.CommandNames(), ","); got != "build,lint,test" {
+		t.Errorf("CommandNames() = %q, want build,lint,test", got)
+	}
+	if r.Commands["test"] != "go test ./..." {
+		t.Errorf("Commands[test] = %q", r.Commands["test"])
+	}
+}
+
+func TestLoad_Diff(t *testing.T) {
+	def := config.Default()
+	if !def.Diff.Local || def.DiffOptions() != (git.DiffOptions{Context: 3, Renames: true}) {
+		t.Errorf("default diff = %+v", def.Diff)
+	}
+	cfg, err := config.Load(writeConfig(t, "diff:\n  local: false\n  context: 10\n  ignore_whitespace: true\n  split: true\n"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Diff.Local || !cfg.Diff.Split || cfg.DiffOptions() != (git.DiffOptions{Context: 10, IgnoreWhitespace: true, Renames: true}) {
+		t.Errorf("diff = %+v", cfg.Diff)
+	}
+	cfg, err = config.Load(writeConfig(t, "diff:\n  pager: delta --width=$COLUMNS\n  difftool: git difftool -d $BASE $HEAD\n"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Diff.Pager != "delta --width=$COLUMNS" || cfg.Diff.Difftool != "git difftool -d $BASE $HEAD" {
+		t.Errorf("diff = %+v", cfg.Diff)
+	}
+	if def.Highlighter() == nil {
+		t.Error("default Highlighter() = nil, want highlighting on")
+	}
+	cfg, err = config.Load(writeConfig(t, "diff:\n  syntax: none\n"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Highlighter() != nil {
+		t.Error("Highlighter() with syntax none is not nil")
+	}
+}
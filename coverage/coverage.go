@@ -0,0 +1,146 @@
+// Package coverage reads coverage profiles and maps them onto the added
+// lines of a diff.
+package coverage
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultFiles are the profiles looked for in a worktree, in order.
+var DefaultFiles = []string{"cover.out", "coverage.out", "coverage.txt", "lcov.info", "coverage/lcov.info"}
+
+// Profile is the line coverage of a set of files.
+type Profile struct {
+	// Files maps each file, named as in the profile, to whether each of its
+	// instrumented lines ran.
+	Files map[string]map[int]bool
+}
+
+// Find returns the first of names, relative to dir, that exists.
+func Find(dir string, names []string) (string, error) {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no coverage profile in %s (looked for %s)", dir, strings.Join(names, ", "))
+}
+
+// ParseFile reads a Go cover profile or an LCOV tracefile.
+func ParseFile(path string) (*Profile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Parse reads a Go cover profile, recognized by its "mode:" line, or an LCOV
+// tracefile.
+func Parse(r io.Reader) (*Profile, error) {
+	p := &Profile{Files: make(map[string]map[int]bool)}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	goProfile, lcov := false, false
+	file := ""
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case line == "":
+		case n == 1 && strings.HasPrefix(line, "mode:"):
+			goProfile = true
+		case goProfile:
+			if err := p.addGoBlock(line); err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+		case strings.HasPrefix(line, "SF:"):
+			lcov = true
+			file = strings.TrimPrefix(line, "SF:")
+		case strings.HasPrefix(line, "DA:") && file != "":
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: invalid DA record %q", n, line)
+			}
+			ln, err1 := strconv.Atoi(fields[0])
+			hits, err2 := strconv.ParseInt(fields[1], 10, 64)
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("line %d: invalid DA record %q", n, line)
+			}
+			p.mark(file, ln, ln, hits > 0)
+		case line == "end_of_record":
+			file = ""
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if !goProfile && !lcov {
+		return nil, errors.New("not a Go cover profile or LCOV tracefile")
+	}
+	return p, nil
+}
+
+// addGoBlock records a block of a Go cover profile:
+// "file.go:startLine.startCol,endLine.endCol numStmt count".
+func (p *Profile) addGoBlock(line string) error {
+	colon := strings.LastIndex(line, ":")
+	if colon < 0 {
+		return fmt.Errorf("invalid block %q", line)
+	}
+	file := line[:colon]
+	var startLine, startCol, endLine, endCol, stmts int
+	var count int64
+	if _, err := fmt.Sscanf(line[colon+1:], "%d.%d,%d.%d %d %d", &startLine, &startCol, &endLine, &endCol, &stmts, &count); err != nil {
+		return fmt.Errorf("invalid block %q", line)
+	}
+	p.mark(file, startLine, endLine, count > 0)
+	return nil
+}
+
+// mark records lines from..to of file. A line stays covered once any block
+// on it ran.
+func (p *Profile) mark(file string, from, to int, covered bool) {
+	lines := p.Files[file]
+	if lines == nil {
+		lines = make(map[int]bool)
+		p.Files[file] = lines
+	}
+	for l := from; l <= to; l++ {
+		lines[l] = lines[l] || covered
+	}
+}
+
+// Lines returns the line coverage of name, a path relative to the repository
+// root. Profiles name files by import path or absolute path, so the shortest
+// profile entry ending in name is used. It returns nil when name is not in
+// the profile.
+func (p *Profile) Lines(name string) map[int]bool {
+	name = filepath.ToSlash(name)
+	best := ""
+	for file := range p.Files {
+		f := filepath.ToSlash(file)
+		if f != name && !strings.HasSuffix(f, "/"+name) {
+			continue
+		}
+		if best == "" || len(file) < len(best) || (len(file) == len(best) && file < best) {
+			best = file
+		}
+	}
+	if best == "" {
+		return nil
+	}
+	return p.Files[best]
+}
@@ -0,0 +1,100 @@
+package coverage_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kosuke9809/gh-review/coverage"
+)
+
+func TestParse_GoProfile(t *testing.T) {
+	p, err := coverage.Parse(strings.NewReader(`mode: set
+example.com/m/a/a.go:3.20,5.2 2 1
+example.com/m/a/a.go:5.2,7.3 1 0
+example.com/m/a/a.go:9.1,9.10 1 0
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := map[int]bool{3: true, 4: true, 5: true, 6: false, 7: false, 9: false}
+	if got := p.Lines("a/a.go"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines(a/a.go) = %v, want %v", got, want)
+	}
+}
+
+func TestParse_LCOV(t *testing.T) {
+	p, err := coverage.Parse(strings.NewReader(`TN:
+SF:/home/me/app/src/index.js
+DA:1,4
+DA:2,0
+end_of_record
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, want := p.Lines("src/index.js"), map[int]bool{1: true, 2: false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines(src/index.js) = %v, want %v", got, want)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"形式不明", "hello\n"},
+		{"壊れたGoのブロック", "mode: set\na.go:1.1 1\n"},
+		{"壊れたDA", "SF:a.js\nDA:x,1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := coverage.Parse(strings.NewReader(tt.in)); err == nil {
+				t.Error("Parse() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestProfile_Lines(t *testing.T) {
+	p := &coverage.Profile{Files: map[string]map[int]bool{
+		"example.com/m/a.go":     {1: true},
+		"example.com/m/sub/a.go": {2: true},
+		"example.com/m/ba.go":    {3: true},
+	}}
+	tests := []struct {
+		name string
+		file string
+		want map[int]bool
+	}{
+		{"ルートのファイル", "a.go", map[int]bool{1: true}},
+		{"サブディレクトリ", "sub/a.go", map[int]bool{2: true}},
+		{"名前の一部には一致しない", "x/ba.go", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Lines(tt.file); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines(%q) = %v, want %v", tt.file, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := coverage.Find(dir, coverage.DefaultFiles); err == nil {
+		t.Error("Find() in an empty directory error = nil, want error")
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "coverage"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(dir, "coverage", "lcov.info")
+	if err := os.WriteFile(want, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := coverage.Find(dir, coverage.DefaultFiles); err != nil || got != want {
+		t.Errorf("Find() = %q, %v; want %q", got, err, want)
+	}
+}
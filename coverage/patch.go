@@ -0,0 +1,91 @@
+package coverage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mark is the coverage of a line of a patch.
+type Mark int
+
+const (
+	MarkNone      Mark = iota // not an added line, or not instrumented
+	MarkCovered               // an added line that ran
+	MarkUncovered             // an added line that did not run
+)
+
+// Annotate marks each line of a unified diff patch. Added lines are matched
+// against lines, the coverage of the file's new version.
+func Annotate(patch string, lines map[int]bool) []Mark {
+	patchLines := strings.Split(patch, "\n")
+	marks := make([]Mark, len(patchLines))
+	newLine := 0
+	for i, line := range patchLines {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			newLine = hunkStart(line)
+		case strings.HasPrefix(line, "+"):
+			if covered, ok := lines[newLine]; ok {
+				marks[i] = MarkUncovered
+				if covered {
+					marks[i] = MarkCovered
+				}
+			}
+			newLine++
+		case strings.HasPrefix(line, "-"), strings.HasPrefix(line, `\`):
+		default:
+			newLine++
+		}
+	}
+	return marks
+}
+
+// hunkStart returns the first new-file line of a hunk header
+// "@@ -a,b +c,d @@".
+func hunkStart(header string) int {
+	i := strings.Index(header, "+")
+	if i < 0 {
+		return 0
+	}
+	var start int
+	if _, err := fmt.Sscanf(header[i+1:], "%d", &start); err != nil {
+		return 0
+	}
+	return start
+}
+
+// Summary counts the added lines of a patch that ran and did not run.
+// Added lines that are not instrumented are not counted.
+type Summary struct {
+	Covered   int
+	Uncovered int
+}
+
+// Summarize counts marks.
+func Summarize(marks []Mark) Summary {
+	var s Summary
+	for _, m := range marks {
+		switch m {
+		case MarkCovered:
+			s.Covered++
+		case MarkUncovered:
+			s.Uncovered++
+		}
+	}
+	return s
+}
+
+// Add returns the sum of s and o.
+func (s Summary) Add(o Summary) Summary {
+	return Summary{Covered: s.Covered + o.Covered, Uncovered: s.Uncovered + o.Uncovered}
+}
+
+// Percent returns the share of instrumented added lines that ran. ok is
+// false when no added line is instrumented.
+func (s Summary) Percent() (pct float64, ok bool) {
+	total := s.Covered + s.Uncovered
+	if total == 0 {
+		return 0, false
+	}
+	return 100 * float64(s.Covered) / float64(total), true
+}
@@ -0,0 +1,32 @@
+package coverage_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kosuke9809/gh-review/coverage"
+)
+
+func TestAnnotate(t *testing.T) {
+	patch := "@@ -1,3 +1,4 @@\n ctx\n-old\n+covered\n+uncovered\n+comment\n@@ -10,2 +11,2 @@ func f() {\n ctx\n+late"
+	lines := map[int]bool{2: true, 3: false, 12: true}
+	want := []coverage.Mark{
+		coverage.MarkNone, coverage.MarkNone, coverage.MarkNone,
+		coverage.MarkCovered, coverage.MarkUncovered, coverage.MarkNone,
+		coverage.MarkNone, coverage.MarkNone, coverage.MarkCovered,
+	}
+	marks := coverage.Annotate(patch, lines)
+	if !reflect.DeepEqual(marks, want) {
+		t.Errorf("Annotate() = %v, want %v", marks, want)
+	}
+	s := coverage.Summarize(marks)
+	if pct, ok := s.Percent(); !ok || s.Covered != 2 || s.Uncovered != 1 || int(pct) != 66 {
+		t.Errorf("Summarize() = %+v (%.1f%%), want 2 covered, 1 uncovered", s, pct)
+	}
+}
+
+func TestSummary_PercentNoData(t *testing.T) {
+	if _, ok := (coverage.Summary{}).Percent(); ok {
+		t.Error("Percent() ok = true without instrumented lines")
+	}
+}
@@ -0,0 +1,196 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ErrNoLocalObjects is returned when the commits of a PR are not available
+// locally, so its diff cannot be computed with git.
+var ErrNoLocalObjects = errors.New("PR commits are not available locally")
+
+// FullFile is a DiffOptions.Context that shows whole files.
+const FullFile = -1
+
+// DiffOptions controls how a local diff is computed.
+type DiffOptions struct {
+	Context          int // lines of context around changes; FullFile shows whole files
+	IgnoreWhitespace bool
+	Renames          bool
+}
+
+// FileDiff is the diff of a single file. Patch starts at the first hunk
+// header, like the patches returned by the GitHub API.
+type FileDiff struct {
+	Filename  string
+	Previous  string // name before a rename; empty otherwise
+	Patch     string
+	Additions int
+	Deletions int
+	Binary    bool
+}
+
+// DiffRange returns the base and head commits of a PR's diff: baseSHA, the
+// base the PR was last compared with on GitHub, and headSHA. Without headSHA
+// locally, the head last fetched into PRRef is used, which may be older.
+// Without baseSHA, the base branch as last fetched from origin is used. With
+// fetch, missing commits are fetched from origin; otherwise
+// ErrNoLocalObjects is returned.
+func DiffRange(repoRoot string, prNumber int, baseRef, baseSHA, headSHA string, fetch bool) (base, head string, err error) {
+	head = headSHA
+	if !hasCommit(repoRoot, head) {
+		switch {
+		case fetch:
+			if head, err = fetchPRHead(repoRoot, prNumber); err != nil {
+				return "", "", fmt.Errorf("%w: %v", ErrNoLocalObjects, err)
+			}
+		case hasCommit(repoRoot, PRRef(prNumber)):
+			if head, err = revParse(repoRoot, PRRef(prNumber)); err != nil {
+				return "", "", err
+			}
+		default:
+			return "", "", ErrNoLocalObjects
+		}
+	}
+	tracking := "refs/remotes/origin/" + baseRef
+	base = baseSHA
+	if base == "" {
+		base = tracking
+	}
+	if !hasCommit(repoRoot, base) {
+		if !fetch {
+			return "", "", ErrNoLocalObjects
+		}
+		// Fetching by SHA needs server support; the base branch holds the
+		// commit too unless it was force-pushed since.
+		if base == baseSHA && exec.Command("git", "-C", repoRoot, "fetch", "origin", baseSHA).Run() == nil && hasCommit(repoRoot, base) {
+			return base, head, nil
+		}
+		spec := fmt.Sprintf("+refs/heads/%s:%s", baseRef, tracking)
+		if err := exec.Command("git", "-C", repoRoot, "fetch", "origin", spec).Run(); err != nil {
+			return "", "", fmt.Errorf("%w: failed to fetch %s: %v", ErrNoLocalObjects, baseRef, err)
+		}
+		if !hasCommit(repoRoot, base) {
+			return "", "", fmt.Errorf("%w: base %s is not on %s", ErrNoLocalObjects, ShortSHA(base), baseRef)
+		}
+	}
+	return base, head, nil
+}
+
+// MergeBase returns the best common ancestor of commits a and b, the base of
+// `git diff a...b`.
+func MergeBase(repoRoot, a, b string) (string, error) {
+	if !hasCommit(repoRoot, a) || !hasCommit(repoRoot, b) {
+		return "", ErrNoLocalObjects
+	}
+	out, err := exec.Command("git", "-C", repoRoot, "merge-base", a, b).Output()
+	if err != nil {
+		return "", fmt.Errorf("git merge-base %s %s: %w", ShortSHA(a), ShortSHA(b), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// FileAt returns the content of path at commit rev, as committed: edits in
+// a worktree do not show. It returns ErrNoLocalObjects when rev is not
+// available locally.
+func FileAt(repoRoot, rev, path string) (string, error) {
+	if !hasCommit(repoRoot, rev) {
+		return "", ErrNoLocalObjects
+	}
+	out, err := exec.Command("git", "-C", repoRoot, "cat-file", "blob", rev+":"+path).Output()
+	if err != nil {
+		return "", fmt.Errorf("%s is not in %s", path, ShortSHA(rev))
+	}
+	return string(out), nil
+}
+
+func hasCommit(dir, rev string) bool {
+	return rev != "" && exec.Command("git", "-C", dir, "cat-file", "-e", rev+"^{commit}").Run() == nil
+}
+
+// Diff returns the changes of head since its merge base with base, as in
+// `git diff base...head`.
+func Diff(repoRoot, base, head string, opts DiffOptions) ([]FileDiff, error) {
+	args := []string{"-C", repoRoot, "-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff"}
+	if opts.Context == FullFile {
+		// Larger than any file, so every hunk spans its whole file.
+		args = append(args, "-U1000000000")
+	} else {
+		args = append(args, "-U"+strconv.Itoa(max(opts.Context, 0)))
+	}
+	if opts.IgnoreWhitespace {
+		args = append(args, "-w")
+	}
+	if opts.Renames {
+		args = append(args, "-M")
+	} else {
+		args = append(args, "--no-renames")
+	}
+	args = append(args, base+"..."+head, "--")
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s...%s: %w", base, head, err)
+	}
+	return parseDiff(string(out)), nil
+}
+
+// parseDiff splits the output of git diff into files.
+func parseDiff(out string) []FileDiff {
+	var files []FileDiff
+	var cur *FileDiff
+	var patch []string
+	inHunks := false
+	flush := func() {
+		if cur != nil {
+			cur.Patch = strings.Join(patch, "\n")
+			files = append(files, *cur)
+		}
+		cur, patch, inHunks = nil, nil, false
+	}
+	sc := bufio.NewScanner(strings.NewReader(out))
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			cur = &FileDiff{}
+			// Only used when no ---/+++ lines follow, e.g. for binary files.
+			if _, b, ok := strings.Cut(line, " b/"); ok {
+				cur.Filename = b
+			}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if inHunks || strings.HasPrefix(line, "@@") {
+			inHunks = true
+			patch = append(patch, line)
+			switch {
+			case strings.HasPrefix(line, "+"):
+				cur.Additions++
+			case strings.HasPrefix(line, "-"):
+				cur.Deletions++
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "rename from "):
+			cur.Previous = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			cur.Filename = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "+++ b/"):
+			cur.Filename = strings.TrimPrefix(line, "+++ b/")
+		case strings.HasPrefix(line, "--- a/") && cur.Filename == "":
+			cur.Filename = strings.TrimPrefix(line, "--- a/")
+		case strings.HasPrefix(line, "Binary files "):
+			cur.Binary = true
+		}
+	}
+	flush()
+	return files
+}
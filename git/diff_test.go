@@ -0,0 +1,119 @@
+package git_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kosuke9809/gh-review/git"
+)
+
+// pushFeaturePR commits changes on a feature branch of origin and points
+// refs/pull/1/head at it, leaving main untouched.
+func pushFeaturePR(t *testing.T, origin string) string {
+	t.Helper()
+	run(t, origin, "checkout", "-q", "-b", "feature")
+	writeFile(t, filepath.Join(origin, "README.md"), "hello\nworld\n")
+	run(t, origin, "mv", "README.md", "DOCS.md")
+	writeFile(t, filepath.Join(origin, "main.go"), "package main\n\nfunc main() {\n\tprintln(1)\n}\n")
+	run(t, origin, "add", ".")
+	run(t, origin, "commit", "-q", "-m", "feature")
+	run(t, origin, "update-ref", "refs/pull/1/head", "HEAD")
+	sha := strings.TrimSpace(run(t, origin, "rev-parse", "HEAD"))
+	run(t, origin, "checkout", "-q", "main")
+	return sha
+}
+
+func TestDiffRange(t *testing.T) {
+	origin, clone := initOrigin(t)
+	sha := pushFeaturePR(t, origin)
+
+	if _, _, err := git.DiffRange(clone, 1, "main", "", sha, false); !errors.Is(err, git.ErrNoLocalObjects) {
+		t.Fatalf("DiffRange() without fetch error = %v, want ErrNoLocalObjects", err)
+	}
+	base, head, err := git.DiffRange(clone, 1, "main", "", sha, true)
+	if err != nil {
+		t.Fatalf("DiffRange() error = %v", err)
+	}
+	if base != "refs/remotes/origin/main" || head != sha {
+		t.Errorf("DiffRange() = %s, %s; want origin/main, %s", base, head, sha)
+	}
+	// The fetched head is found afterwards, even by an older SHA.
+	if _, head, err := git.DiffRange(clone, 1, "main", "", "0000000000000000000000000000000000000000", false); err != nil || head != sha {
+		t.Errorf("DiffRange() after fetch = %s, %v; want %s", head, err, sha)
+	}
+}
+
+func TestDiffRange_BaseSHA(t *testing.T) {
+	origin, clone := initOrigin(t)
+	sha := pushFeaturePR(t, origin)
+	// The base branch moves on after the clone, leaving origin/main stale.
+	baseSHA := commitIn(t, origin, "later.txt")
+
+	if _, _, err := git.DiffRange(clone, 1, "main", baseSHA, sha, false); !errors.Is(err, git.ErrNoLocalObjects) {
+		t.Fatalf("DiffRange() without fetch error = %v, want ErrNoLocalObjects", err)
+	}
+	base, _, err := git.DiffRange(clone, 1, "main", baseSHA, sha, true)
+	if err != nil {
+		t.Fatalf("DiffRange() error = %v", err)
+	}
+	if base != baseSHA {
+		t.Errorf("DiffRange() base = %s, want the PR's base %s", base, baseSHA)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	origin, clone := initOrigin(t)
+	sha := pushFeaturePR(t, origin)
+	base, head, err := git.DiffRange(clone, 1, "main", "", sha, true)
+	if err != nil {
+		t.Fatalf("DiffRange() error = %v", err)
+	}
+
+	files, err := git.Diff(clone, base, head, git.DiffOptions{Context: 3, Renames: true})
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Diff() = %+v, want 2 files", files)
+	}
+	docs, main := files[0], files[1]
+	if docs.Filename != "DOCS.md" || docs.Previous != "README.md" || docs.Additions != 1 || docs.Deletions != 0 {
+		t.Errorf("renamed file = %+v", docs)
+	}
+	if !strings.HasPrefix(docs.Patch, "@@ -1 +1,2 @@") {
+		t.Errorf("patch = %q, want it to start at the hunk header", docs.Patch)
+	}
+	if main.Filename != "main.go" || main.Additions != 5 {
+		t.Errorf("added file = %+v", main)
+	}
+
+	files, err = git.Diff(clone, base, head, git.DiffOptions{Context: 3})
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if len(files) != 3 {
+		t.Errorf("Diff() without renames = %d files, want delete and add", len(files))
+	}
+}
+
+func TestMergeBase_FileAt(t *testing.T) {
+	origin, clone := initOrigin(t)
+	fork := strings.TrimSpace(run(t, origin, "rev-parse", "main"))
+	sha := pushFeaturePR(t, origin)
+	later := commitIn(t, origin, "later.txt")
+	if _, _, err := git.DiffRange(clone, 1, "main", later, sha, true); err != nil {
+		t.Fatalf("DiffRange() error = %v", err)
+	}
+
+	if got, err := git.MergeBase(clone, later, sha); err != nil || got != fork {
+		t.Errorf("MergeBase() = %s, %v; want %s", got, err, fork)
+	}
+	if got, err := git.FileAt(clone, sha, "DOCS.md"); err != nil || got != "hello\nworld\n" {
+		t.Errorf("FileAt() = %q, %v", got, err)
+	}
+	if _, err := git.FileAt(clone, "0000000000000000000000000000000000000000", "DOCS.md"); !errors.Is(err, git.ErrNoLocalObjects) {
+		t.Errorf("FileAt() of a missing commit error = %v, want ErrNoLocalObjects", err)
+	}
+}
@@ -0,0 +1,124 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Layout decides where PR worktrees are created.
+type Layout struct {
+	Dir  string // base directory; "~" is the home directory and relative paths are under the repository root
+	Name string // path under Dir; see LayoutVars for placeholders
+}
+
+// DefaultLayout places worktrees at <repoRoot>/.worktrees/pr-<number>.
+func DefaultLayout() Layout {
+	return Layout{Dir: ".worktrees", Name: "pr-{number}"}
+}
+
+// LayoutVars are the values substituted into a Layout's Name template as
+// {owner}, {repo}, {number}, {headRef} and {author}.
+type LayoutVars struct {
+	Owner   string
+	Repo    string
+	Number  int
+	HeadRef string
+	Author  string
+}
+
+var placeholderRe = regexp.MustCompile(`\{([A-Za-z]+)\}`)
+
+func (v LayoutVars) value(name string) (string, bool) {
+	switch name {
+	case "owner":
+		return v.Owner, true
+	case "repo":
+		return v.Repo, true
+	case "number":
+		return strconv.Itoa(v.Number), true
+	case "headRef":
+		// Branch names may contain slashes; keep them to one path element.
+		return strings.ReplaceAll(v.HeadRef, "/", "-"), true
+	case "author":
+		return v.Author, true
+	}
+	return "", false
+}
+
+// Validate checks that Name only uses known placeholders and includes
+// {number}, which is how worktrees are matched back to their PRs.
+func (l Layout) Validate() error {
+	if strings.TrimSpace(l.Dir) == "" {
+		return fmt.Errorf("worktree dir must not be empty")
+	}
+	if filepath.IsAbs(l.Name) {
+		return fmt.Errorf("worktree name %q must be relative to dir", l.Name)
+	}
+	for _, m := range placeholderRe.FindAllStringSubmatch(l.Name, -1) {
+		if _, ok := (LayoutVars{}).value(m[1]); !ok {
+			return fmt.Errorf("worktree name %q: unknown placeholder {%s}", l.Name, m[1])
+		}
+	}
+	if !strings.Contains(l.Name, "{number}") {
+		return fmt.Errorf("worktree name %q must contain {number}", l.Name)
+	}
+	return nil
+}
+
+// base returns the absolute base directory for repoRoot.
+func (l Layout) base(repoRoot string) string {
+	dir := l.Dir
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, dir[1:])
+		}
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoRoot, dir)
+	}
+	return filepath.Clean(dir)
+}
+
+// Path returns where the worktree for the PR described by v belongs.
+func (l Layout) Path(repoRoot string, v LayoutVars) string {
+	name := placeholderRe.ReplaceAllStringFunc(l.Name, func(p string) string {
+		s, _ := v.value(p[1 : len(p)-1])
+		return s
+	})
+	return filepath.Join(l.base(repoRoot), filepath.FromSlash(name))
+}
+
+// Match reports the PR number of a worktree at path created with this
+// layout. Placeholders other than {number} match any non-empty path element
+// fragment, so worktrees are found even if the other values are unknown.
+func (l Layout) Match(repoRoot, path string) (int, bool) {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	pattern.WriteString(regexp.QuoteMeta(filepath.ToSlash(l.base(repoRoot)) + "/"))
+	name := filepath.ToSlash(l.Name)
+	last := 0
+	for _, loc := range placeholderRe.FindAllStringSubmatchIndex(name, -1) {
+		pattern.WriteString(regexp.QuoteMeta(name[last:loc[0]]))
+		if name[loc[2]:loc[3]] == "number" {
+			pattern.WriteString(`(?P<number>\d+)`)
+		} else {
+			pattern.WriteString(`[^/]+`)
+		}
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(name[last:]) + "$")
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return 0, false
+	}
+	m := re.FindStringSubmatch(filepath.ToSlash(filepath.Clean(path)))
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[re.SubexpIndex("number")])
+	return n, err == nil
+}
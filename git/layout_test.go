@@ -0,0 +1,139 @@
+package git_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kosuke9809/gh-review/git"
+)
+
+// wtPath returns the default-layout worktree path of a PR.
+func wtPath(repoRoot string, n int) string {
+	return git.DefaultLayout().Path(repoRoot, git.LayoutVars{Number: n})
+}
+
+func TestLayout_Path(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	vars := git.LayoutVars{Owner: "octo", Repo: "app", Number: 142, HeadRef: "feature/login", Author: "alice"}
+	tests := []struct {
+		name   string
+		layout git.Layout
+		want   string
+	}{
+		{"デフォルト", git.DefaultLayout(), "/repo/root/.worktrees/pr-142"},
+		{"ホームディレクトリ配下", git.Layout{Dir: "~/worktrees", Name: "{owner}/{repo}/{number}-{headRef}"}, filepath.Join(home, "worktrees/octo/app/142-feature-login")},
+		{"絶対パス", git.Layout{Dir: "/wt", Name: "{author}-{number}"}, "/wt/alice-142"},
+		{"相対パス", git.Layout{Dir: "../wt", Name: "{repo}-{number}"}, "/repo/wt/app-142"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.layout.Path("/repo/root", vars); got != tt.want {
+				t.Errorf("Path() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayout_Match(t *testing.T) {
+	layout := git.Layout{Dir: "/wt", Name: "{owner}/{repo}/{number}-{headRef}"}
+	tests := []struct {
+		name   string
+		layout git.Layout
+		path   string
+		want   int
+		wantOK bool
+	}{
+		{"テンプレートに一致", layout, "/wt/octo/app/142-feature-login", 142, true},
+		{"番号以外が異なる値", layout, "/wt/other/repo/7-fix", 7, true},
+		{"番号が数字でない", layout, "/wt/octo/app/x-fix", 0, false},
+		{"ディレクトリが深すぎる", layout, "/wt/octo/app/sub/142-fix", 0, false},
+		{"デフォルト", git.DefaultLayout(), "/repo/root/.worktrees/pr-12", 12, true},
+		{"デフォルト: 別の場所", git.DefaultLayout(), "/repo/other/pr-12", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.layout.Match("/repo/root", tt.path)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Match(%q) = %d, %v; want %d, %v", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLayout_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  git.Layout
+		wantErr bool
+	}{
+		{"デフォルト", git.DefaultLayout(), false},
+		{"番号なし", git.Layout{Dir: "/wt", Name: "{headRef}"}, true},
+		{"未知のプレースホルダ", git.Layout{Dir: "/wt", Name: "{number}-{title}"}, true},
+		{"dirが空", git.Layout{Name: "{number}"}, true},
+		{"nameが絶対パス", git.Layout{Dir: "/wt", Name: "/tmp/{number}"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.layout.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFindWorktree(t *testing.T) {
+	root := initRepo(t)
+	layout := git.Layout{Dir: filepath.Join(filepath.Dir(root), "wt"), Name: "{repo}/{number}-{headRef}"}
+	configured := layout.Path(root, git.LayoutVars{Repo: "app", Number: 3, HeadRef: "fix"})
+	if err := os.MkdirAll(filepath.Dir(configured), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	run(t, root, "worktree", "add", "-q", "--detach", configured, "HEAD")
+	legacy := wtPath(root, 4)
+	run(t, root, "worktree", "add", "-q", "--detach", legacy, "HEAD")
+
+	for n, want := range map[int]string{3: configured, 4: legacy} {
+		wt, ok := git.FindWorktree(root, layout, n)
+		if !ok || wt.Path != want {
+			t.Errorf("FindWorktree(%d) = %q, %v; want %q", n, wt.Path, ok, want)
+		}
+	}
+	if git.WorktreeExists(root, layout, 5) {
+		t.Error("WorktreeExists(5) = true, want false")
+	}
+
+	moved := layout.Path(root, git.LayoutVars{Repo: "app", Number: 4, HeadRef: "other"})
+	if err := git.MoveWorktree(root, legacy, moved); err != nil {
+		t.Fatalf("MoveWorktree() error = %v", err)
+	}
+	if wt, ok := git.FindWorktree(root, layout, 4); !ok || wt.Path != moved {
+		t.Errorf("FindWorktree(4) after move = %q, %v; want %q", wt.Path, ok, moved)
+	}
+}
+
+func TestPRWorktrees_ExactMatch(t *testing.T) {
+	root := initRepo(t)
+	run(t, root, "worktree", "add", "-q", "--detach", wtPath(root, 12), "HEAD")
+
+	// pr-12 must not be taken for pr-1.
+	if wt, ok := git.FindWorktree(root, git.DefaultLayout(), 1); ok {
+		t.Fatalf("FindWorktree(1) = %q, want none", wt.Path)
+	}
+
+	run(t, root, "worktree", "add", "-q", "--detach", wtPath(root, 1), "HEAD")
+	run(t, root, "worktree", "add", "-q", "--detach", wtPath(root, 5), "HEAD")
+	if err := os.RemoveAll(wtPath(root, 5)); err != nil {
+		t.Fatal(err)
+	}
+	wts, err := git.PRWorktrees(root, git.DefaultLayout())
+	if err != nil {
+		t.Fatalf("PRWorktrees() error = %v", err)
+	}
+	if len(wts) != 2 || wts[1].Path != wtPath(root, 1) || wts[12].Path != wtPath(root, 12) {
+		t.Errorf("PRWorktrees() = %+v, want pr-1 and pr-12 without the removed pr-5", wts)
+	}
+}
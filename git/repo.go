@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
@@ -64,40 +65,76 @@ func RemoteURL(remote string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
-// WorktreePath returns the path for a PR's worktree.
-func WorktreePath(repoRoot string, prNumber int) string {
-	return filepath.Join(repoRoot, ".worktrees", fmt.Sprintf("pr-%d", prNumber))
+// PRWorktrees returns the PR worktrees placed by layout or by the default
+// layout, keyed by PR number, from a single `git worktree list`. Worktrees
+// whose directory no longer exists are left out.
+func PRWorktrees(repoRoot string, layout Layout) (map[int]Worktree, error) {
+	wts, err := ListWorktrees(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int]Worktree)
+	for _, wt := range wts {
+		if wt.Prunable {
+			continue
+		}
+		if n, ok := matchLayouts(repoRoot, layout, wt.Path); ok {
+			result[n] = wt
+		}
+	}
+	return result, nil
 }
 
-// WorktreeExists reports whether the worktree for the given PR number exists.
-func WorktreeExists(repoRoot string, prNumber int) bool {
-	path := WorktreePath(repoRoot, prNumber)
-	out, err := exec.Command("git", "worktree", "list", "--porcelain").Output()
+// FindWorktree returns the worktree of a PR. To look up many PRs, use
+// PRWorktrees instead.
+func FindWorktree(repoRoot string, layout Layout, prNumber int) (Worktree, bool) {
+	wts, err := PRWorktrees(repoRoot, layout)
 	if err != nil {
-		return false
+		return Worktree{}, false
 	}
-	return strings.Contains(string(out), path)
+	wt, ok := wts[prNumber]
+	return wt, ok
+}
+
+// WorktreeExists reports whether a worktree for the given PR number exists.
+func WorktreeExists(repoRoot string, layout Layout, prNumber int) bool {
+	_, ok := FindWorktree(repoRoot, layout, prNumber)
+	return ok
 }
 
-// CreateWorktree creates a git worktree for the given PR.
-func CreateWorktree(repoRoot string, prNumber int) error {
-	path := WorktreePath(repoRoot, prNumber)
+// PRRef returns the local ref that holds the last fetched head of a PR.
+func PRRef(prNumber int) string {
+	return fmt.Sprintf("refs/gh-review/pr/%d", prNumber)
+}
+
+// fetchPRHead fetches the PR's head into PRRef and returns its SHA.
+func fetchPRHead(repoRoot string, prNumber int) (string, error) {
 	ref := fmt.Sprintf("refs/pull/%d/head", prNumber)
-	// Fetch the ref first
-	if err := exec.Command("git", "fetch", "origin", ref).Run(); err != nil {
-		return fmt.Errorf("failed to fetch %s: %w", ref, err)
+	if err := exec.Command("git", "-C", repoRoot, "fetch", "origin", "+"+ref+":"+PRRef(prNumber)).Run(); err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", ref, err)
 	}
-	if err := exec.Command("git", "worktree", "add", "--detach", path, "FETCH_HEAD").Run(); err != nil {
-		return fmt.Errorf("failed to create worktree at %s: %w", path, err)
+	return revParse(repoRoot, PRRef(prNumber))
+}
+
+func revParse(dir, rev string) (string, error) {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--verify", "-q", rev+"^{commit}").Output()
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve %s: %w", rev, err)
 	}
-	return nil
+	return strings.TrimSpace(string(out)), nil
 }
 
-// RemoveWorktree removes the git worktree for the given PR number.
-func RemoveWorktree(repoRoot string, prNumber int) error {
-	path := WorktreePath(repoRoot, prNumber)
-	if err := exec.Command("git", "worktree", "remove", "--force", path).Run(); err != nil {
-		return fmt.Errorf("failed to remove worktree at %s: %w", path, err)
+// CreateWorktree creates a git worktree for the given PR at path.
+func CreateWorktree(repoRoot, path string, prNumber int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	sha, err := fetchPRHead(repoRoot, prNumber)
+	if err != nil {
+		return err
+	}
+	if err := exec.Command("git", "-C", repoRoot, "worktree", "add", "--detach", path, sha).Run(); err != nil {
+		return fmt.Errorf("failed to create worktree at %s: %w", path, err)
 	}
 	return nil
 }
@@ -60,16 +60,8 @@ func TestParseHostOwnerRepo(t *testing.T) {
 	}
 }
 
-func TestWorktreePath(t *testing.T) {
-	path := git.WorktreePath("/repo/root", 142)
-	want := "/repo/root/.worktrees/pr-142"
-	if path != want {
-		t.Errorf("WorktreePath = %q, want %q", path, want)
-	}
-}
-
-func TestRemoveWorktree_NonExistent(t *testing.T) {
-	err := git.RemoveWorktree("/tmp/nonexistent-repo-gh-review-test", 99999)
+func TestRemoveWorktreeAt_NonExistent(t *testing.T) {
+	err := git.RemoveWorktreeAt("/tmp/nonexistent-repo-gh-review-test", "/tmp/nonexistent-repo-gh-review-test/.worktrees/pr-99999")
 	if err == nil {
 		t.Error("expected error for non-existent worktree, got nil")
 	}
@@ -0,0 +1,208 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrForkNotEditable is returned when a PR from a fork does not allow
+// maintainers to push to its branch.
+var ErrForkNotEditable = errors.New("the PR's fork does not allow edits from maintainers")
+
+// ErrBranchCheckedOut is returned when the local branch tracking the PR head
+// is already checked out in another worktree.
+var ErrBranchCheckedOut = errors.New("branch is already checked out")
+
+// TrackingSource describes where a PR's head branch lives.
+type TrackingSource struct {
+	PRNumber int
+	Path     string // where to create the worktree
+	Branch   string // PR head branch name
+	Fork     bool   // head branch is in a fork of the base repository
+	Owner    string // fork owner, used as the remote name
+	CloneURL string // fork HTTPS URL
+	SSHURL   string // fork SSH URL
+	Editable bool   // maintainers may push to the fork's branch
+}
+
+// remote returns the name of the remote holding the head branch.
+func (s TrackingSource) remote() string {
+	if s.Fork {
+		return s.Owner
+	}
+	return "origin"
+}
+
+// CreateTrackingWorktree creates the PR's worktree on a local branch that
+// tracks the PR head branch, so `git push` from the worktree updates the PR.
+// For a fork, the fork is added as a remote named after its owner, and the
+// local branch is named <owner>-<branch>; a push refspec on that remote maps
+// it to the fork's branch.
+func CreateTrackingWorktree(repoRoot string, src TrackingSource) error {
+	if src.Branch == "" {
+		return errors.New("PR head branch is unknown")
+	}
+	remote := src.remote()
+	if src.Fork {
+		if !src.Editable {
+			return ErrForkNotEditable
+		}
+		if err := ensureForkRemote(repoRoot, src); err != nil {
+			return err
+		}
+	}
+	refspec := fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", src.Branch, remote, src.Branch)
+	if out, err := exec.Command("git", "-C", repoRoot, "fetch", remote, refspec).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to fetch %s from %s: %w: %s", src.Branch, remote, err, strings.TrimSpace(string(out)))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(src.Path), 0o755); err != nil {
+		return err
+	}
+	path := src.Path
+	upstream := remote + "/" + src.Branch
+	branch, exists, err := src.localBranch(repoRoot)
+	if err != nil {
+		return err
+	}
+	args := []string{"-C", repoRoot, "worktree", "add", "--track", "-b", branch, path, upstream}
+	if exists {
+		args = []string{"-C", repoRoot, "worktree", "add", path, branch}
+	}
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create worktree at %s: %w: %s", path, err, strings.TrimSpace(string(out)))
+	}
+	if branch != src.Branch {
+		// The default push.default=simple refuses to push to an upstream of
+		// another name, so the fork's remote says where the branch goes.
+		if err := addPushRefspec(repoRoot, remote, fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, src.Branch)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// localBranch picks the local branch for the worktree: the head branch's
+// name, prefixed with the fork owner for forks so that a fork's main does not
+// clash with ours. For forks, pr-<number>-<branch> is used when that name is
+// taken by a branch tracking something else; origin has no room for a push
+// refspec, which would change how every other branch pushes. exists reports
+// whether the branch already tracks the PR head and is reused.
+func (s TrackingSource) localBranch(repoRoot string) (name string, exists bool, err error) {
+	names := []string{s.Branch}
+	if s.Fork {
+		names = []string{s.Owner + "-" + s.Branch, fmt.Sprintf("pr-%d-%s", s.PRNumber, s.Branch)}
+	}
+	for _, name := range names {
+		if exec.Command("git", "-C", repoRoot, "rev-parse", "--verify", "-q", "refs/heads/"+name).Run() != nil {
+			return name, false, nil
+		}
+		// Reuse an existing branch only when it already tracks the PR head;
+		// otherwise pushing from it would not update the PR.
+		if configValue(repoRoot, "branch."+name+".remote") == s.remote() &&
+			configValue(repoRoot, "branch."+name+".merge") == "refs/heads/"+s.Branch {
+			if err := checkNotCheckedOut(repoRoot, name); err != nil {
+				return "", false, err
+			}
+			return name, true, nil
+		}
+	}
+	return "", false, fmt.Errorf("local branch %s already exists and does not track %s/%s", strings.Join(names, " and "), s.remote(), s.Branch)
+}
+
+// checkNotCheckedOut returns ErrBranchCheckedOut, naming the worktree, when
+// branch is checked out in any worktree of the repository.
+func checkNotCheckedOut(repoRoot, branch string) error {
+	wts, err := ListWorktrees(repoRoot)
+	if err != nil {
+		return err
+	}
+	for _, wt := range wts {
+		if wt.Branch == branch {
+			return fmt.Errorf("%w: %s at %s", ErrBranchCheckedOut, branch, wt.Path)
+		}
+	}
+	return nil
+}
+
+// addPushRefspec adds refspec to the push refspecs of remote unless it is
+// there already.
+func addPushRefspec(repoRoot, remote, refspec string) error {
+	out, _ := exec.Command("git", "-C", repoRoot, "config", "--get-all", "remote."+remote+".push").Output()
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.TrimSpace(line) == refspec {
+			return nil
+		}
+	}
+	if out, err := exec.Command("git", "-C", repoRoot, "config", "--add", "remote."+remote+".push", refspec).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to set the push refspec of %s: %w: %s", remote, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ensureForkRemote adds the fork as a remote, using SSH when origin does.
+// An existing remote of the same name must point at the fork.
+func ensureForkRemote(repoRoot string, src TrackingSource) error {
+	url := src.CloneURL
+	if isSSH(configValue(repoRoot, "remote.origin.url")) && src.SSHURL != "" {
+		url = src.SSHURL
+	}
+	if existing := configValue(repoRoot, "remote."+src.Owner+".url"); existing != "" {
+		if existing != src.CloneURL && existing != src.SSHURL {
+			return fmt.Errorf("remote %s already exists for %s", src.Owner, existing)
+		}
+		return nil
+	}
+	if out, err := exec.Command("git", "-C", repoRoot, "remote", "add", src.Owner, url).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to add remote %s: %w: %s", src.Owner, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func isSSH(url string) bool {
+	return strings.HasPrefix(url, "ssh://") || scpLikeRe.MatchString(url) && !strings.Contains(url, "://")
+}
+
+func configValue(repoRoot, key string) string {
+	out, err := exec.Command("git", "-C", repoRoot, "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// BranchStatus is the push state of a worktree checked out on a branch.
+type BranchStatus struct {
+	Branch   string
+	Upstream string // e.g. "origin/fix"; empty when the branch tracks nothing
+	Ahead    int    // local commits not pushed
+	Behind   int    // upstream commits not pulled, as of the last fetch
+}
+
+// WorktreeBranchStatus returns the branch and push state of the worktree at
+// path. ok is false when the worktree is detached.
+func WorktreeBranchStatus(path string) (status BranchStatus, ok bool, err error) {
+	out, err := exec.Command("git", "-C", path, "symbolic-ref", "-q", "--short", "HEAD").Output()
+	if err != nil {
+		return BranchStatus{}, false, nil
+	}
+	status.Branch = strings.TrimSpace(string(out))
+	out, err = exec.Command("git", "-C", path, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}").Output()
+	if err != nil {
+		return status, true, nil
+	}
+	status.Upstream = strings.TrimSpace(string(out))
+	out, err = exec.Command("git", "-C", path, "rev-list", "--left-right", "--count", "HEAD...@{upstream}").Output()
+	if err != nil {
+		return status, true, fmt.Errorf("compare %s with %s: %w", status.Branch, status.Upstream, err)
+	}
+	ahead, behind, _ := strings.Cut(strings.TrimSpace(string(out)), "\t")
+	status.Ahead, _ = strconv.Atoi(ahead)
+	status.Behind, _ = strconv.Atoi(behind)
+	return status, true, nil
+}
@@ -0,0 +1,136 @@
+package git_test
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kosuke9809/gh-review/git"
+)
+
+// commitIn commits a new file in dir and returns the new HEAD.
+func commitIn(t *testing.T, dir, name string) string {
+	t.Helper()
+	writeFile(t, filepath.Join(dir, name), name+"\n")
+	run(t, dir, "add", ".")
+	run(t, dir, "commit", "-q", "-m", name)
+	return strings.TrimSpace(run(t, dir, "rev-parse", "HEAD"))
+}
+
+func TestCreateTrackingWorktree_SameRepo(t *testing.T) {
+	origin, clone := initOrigin(t)
+	run(t, origin, "branch", "feature")
+
+	src := git.TrackingSource{PRNumber: 1, Path: wtPath(clone, 1), Branch: "feature"}
+	if err := git.CreateTrackingWorktree(clone, src); err != nil {
+		t.Fatalf("CreateTrackingWorktree() error = %v", err)
+	}
+	wt := wtPath(clone, 1)
+	sha := commitIn(t, wt, "fix.txt")
+
+	st, ok, err := git.WorktreeBranchStatus(wt)
+	if err != nil || !ok {
+		t.Fatalf("WorktreeBranchStatus() = %+v, %v, %v", st, ok, err)
+	}
+	want := git.BranchStatus{Branch: "feature", Upstream: "origin/feature", Ahead: 1}
+	if st != want {
+		t.Errorf("WorktreeBranchStatus() = %+v, want %+v", st, want)
+	}
+
+	run(t, wt, "push", "-q")
+	if got := strings.TrimSpace(run(t, origin, "rev-parse", "feature")); got != sha {
+		t.Errorf("origin feature = %s, want pushed %s", got, sha)
+	}
+}
+
+func TestCreateTrackingWorktree_Fork(t *testing.T) {
+	origin, clone := initOrigin(t)
+	fork := filepath.Join(t.TempDir(), "fork")
+	if out, err := exec.Command("git", "clone", "-q", origin, fork).CombinedOutput(); err != nil {
+		t.Fatalf("clone: %v\n%s", err, out)
+	}
+	run(t, fork, "branch", "patch")
+
+	src := git.TrackingSource{PRNumber: 1, Path: wtPath(clone, 1), Branch: "patch", Fork: true, Owner: "alice", CloneURL: fork, SSHURL: "git@example.com:alice/fork.git"}
+	if err := git.CreateTrackingWorktree(clone, src); !errors.Is(err, git.ErrForkNotEditable) {
+		t.Fatalf("CreateTrackingWorktree() without edits error = %v, want ErrForkNotEditable", err)
+	}
+
+	src.Editable = true
+	if err := git.CreateTrackingWorktree(clone, src); err != nil {
+		t.Fatalf("CreateTrackingWorktree() error = %v", err)
+	}
+	if got := strings.TrimSpace(run(t, clone, "remote", "get-url", "alice")); got != fork {
+		t.Errorf("remote alice = %s, want %s", got, fork)
+	}
+	wt := wtPath(clone, 1)
+	if got := strings.TrimSpace(run(t, wt, "branch", "--show-current")); got != "alice-patch" {
+		t.Errorf("worktree branch = %s, want alice-patch", got)
+	}
+	sha := commitIn(t, wt, "fix.txt")
+	run(t, wt, "push", "-q")
+	if got := strings.TrimSpace(run(t, fork, "rev-parse", "patch")); got != sha {
+		t.Errorf("fork patch = %s, want pushed %s", got, sha)
+	}
+}
+
+func TestCreateTrackingWorktree_ExistingBranch(t *testing.T) {
+	origin, clone := initOrigin(t)
+	fork := filepath.Join(t.TempDir(), "fork")
+	if out, err := exec.Command("git", "clone", "-q", origin, fork).CombinedOutput(); err != nil {
+		t.Fatalf("clone: %v\n%s", err, out)
+	}
+	run(t, fork, "branch", "patch")
+	run(t, clone, "branch", "alice-patch")
+
+	src := git.TrackingSource{PRNumber: 1, Path: wtPath(clone, 1), Branch: "patch", Fork: true, Owner: "alice", CloneURL: fork, Editable: true}
+	if err := git.CreateTrackingWorktree(clone, src); err != nil {
+		t.Fatalf("CreateTrackingWorktree() error = %v", err)
+	}
+	wt := wtPath(clone, 1)
+	if got := strings.TrimSpace(run(t, wt, "branch", "--show-current")); got != "pr-1-patch" {
+		t.Errorf("worktree branch = %s, want pr-1-patch", got)
+	}
+	sha := commitIn(t, wt, "fix.txt")
+	run(t, wt, "push", "-q")
+	if got := strings.TrimSpace(run(t, fork, "rev-parse", "patch")); got != sha {
+		t.Errorf("fork patch = %s, want pushed %s", got, sha)
+	}
+	if got := strings.TrimSpace(run(t, clone, "rev-parse", "alice-patch")); got == sha {
+		t.Errorf("local alice-patch moved to %s, want it left alone", got)
+	}
+	if exec.Command("git", "-C", clone, "config", "--get", "extensions.worktreeConfig").Run() == nil {
+		t.Error("extensions.worktreeConfig set, want the repository format left alone")
+	}
+
+	// origin has no push refspec to spare for a renamed branch.
+	run(t, origin, "branch", "feature")
+	run(t, clone, "branch", "feature")
+	if err := git.CreateTrackingWorktree(clone, git.TrackingSource{PRNumber: 2, Path: wtPath(clone, 2), Branch: "feature"}); err == nil {
+		t.Error("CreateTrackingWorktree() over an unrelated local branch succeeded, want an error")
+	}
+}
+
+func TestCreateTrackingWorktree_BranchCheckedOut(t *testing.T) {
+	origin, clone := initOrigin(t)
+	run(t, origin, "branch", "feature")
+	run(t, clone, "fetch", "-q")
+	run(t, clone, "checkout", "-q", "-b", "feature", "--track", "origin/feature")
+
+	err := git.CreateTrackingWorktree(clone, git.TrackingSource{PRNumber: 1, Path: wtPath(clone, 1), Branch: "feature"})
+	if !errors.Is(err, git.ErrBranchCheckedOut) || !strings.Contains(err.Error(), clone) {
+		t.Errorf("CreateTrackingWorktree() error = %v, want ErrBranchCheckedOut naming %s", err, clone)
+	}
+}
+
+func TestWorktreeBranchStatus_Detached(t *testing.T) {
+	_, clone := initOrigin(t)
+	if err := git.CreateWorktree(clone, wtPath(clone, 1), 1); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	if _, ok, err := git.WorktreeBranchStatus(wtPath(clone, 1)); ok || err != nil {
+		t.Errorf("WorktreeBranchStatus() ok = %v, err = %v; want detached", ok, err)
+	}
+}
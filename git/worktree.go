@@ -0,0 +1,414 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Worktree is one entry of `git worktree list --porcelain`.
+type Worktree struct {
+	Path     string
+	HEAD     string
+	Branch   string // short branch name; empty when detached
+	Detached bool
+	Bare     bool
+	Locked   bool
+	Prunable bool // the worktree directory is gone; `git worktree prune` would drop it
+}
+
+// ListWorktrees returns every worktree registered in the repository.
+func ListWorktrees(repoRoot string) ([]Worktree, error) {
+	out, err := exec.Command("git", "-C", repoRoot, "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		return nil, fmt.Errorf("list worktrees: %w", err)
+	}
+	return parseWorktreeList(string(out)), nil
+}
+
+func parseWorktreeList(out string) []Worktree {
+	var result []Worktree
+	var cur *Worktree
+	sc := bufio.NewScanner(strings.NewReader(out))
+	for sc.Scan() {
+		line := sc.Text()
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "worktree":
+			result = append(result, Worktree{Path: value})
+			cur = &result[len(result)-1]
+		case "HEAD":
+			if cur != nil {
+				cur.HEAD = value
+			}
+		case "branch":
+			if cur != nil {
+				cur.Branch = strings.TrimPrefix(value, "refs/heads/")
+			}
+		case "detached":
+			if cur != nil {
+				cur.Detached = true
+			}
+		case "bare":
+			if cur != nil {
+				cur.Bare = true
+			}
+		case "locked":
+			if cur != nil {
+				cur.Locked = true
+			}
+		case "prunable":
+			if cur != nil {
+				cur.Prunable = true
+			}
+		}
+	}
+	return result
+}
+
+// WorktreeStatus describes a PR worktree for the Worktrees screen.
+type WorktreeStatus struct {
+	Worktree
+	PRNumber  int
+	Dirty     bool
+	DiskUsage int64
+	LastUsed  time.Time
+}
+
+// ManagedWorktrees returns the PR worktrees placed by layout or by the
+// default layout, with their dirty state, disk usage and last modification
+// time.
+func ManagedWorktrees(repoRoot string, layout Layout) ([]WorktreeStatus, error) {
+	wts, err := ListWorktrees(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	var result []WorktreeStatus
+	for _, wt := range wts {
+		n, ok := matchLayouts(repoRoot, layout, wt.Path)
+		if !ok {
+			continue
+		}
+		st := WorktreeStatus{Worktree: wt, PRNumber: n}
+		st.Dirty, _ = IsDirty(wt.Path)
+		st.DiskUsage, st.LastUsed = cachedDiskUsage(wt, st.Dirty)
+		result = append(result, st)
+	}
+	return result, nil
+}
+
+// matchLayouts matches path against layout, falling back to the default
+// layout so worktrees created before a layout change are still found.
+func matchLayouts(repoRoot string, layout Layout, path string) (int, bool) {
+	if n, ok := layout.Match(repoRoot, path); ok {
+		return n, true
+	}
+	return DefaultLayout().Match(repoRoot, path)
+}
+
+// MoveWorktree moves a worktree to a new path, creating parent directories.
+func MoveWorktree(repoRoot, from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		return err
+	}
+	if out, err := exec.Command("git", "-C", repoRoot, "worktree", "move", from, to).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to move worktree %s to %s: %w: %s", from, to, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// IsDirty reports whether the worktree has uncommitted or untracked changes.
+func IsDirty(path string) (bool, error) {
+	out, err := exec.Command("git", "-C", path, "status", "--porcelain").Output()
+	if err != nil {
+		return false, fmt.Errorf("status %s: %w", path, err)
+	}
+	return len(strings.TrimSpace(string(out))) > 0, nil
+}
+
+// usageKey identifies a worktree at a commit in usageCache.
+type usageKey struct {
+	path string
+	head string
+}
+
+// usage is the result of diskUsage.
+type usage struct {
+	size   int64
+	latest time.Time
+}
+
+// usageCache holds the disk usage of clean worktrees by path and HEAD, so
+// reloading the Worktrees screen does not walk every worktree again.
+var usageCache sync.Map
+
+// cachedDiskUsage returns the disk usage of wt, walking it only when it is
+// dirty or its HEAD moved since the last walk.
+func cachedDiskUsage(wt Worktree, dirty bool) (int64, time.Time) {
+	key := usageKey{path: wt.Path, head: wt.HEAD}
+	if !dirty {
+		if u, ok := usageCache.Load(key); ok {
+			return u.(usage).size, u.(usage).latest
+		}
+	}
+	size, latest := diskUsage(wt.Path)
+	if dirty {
+		usageCache.Delete(key)
+	} else {
+		usageCache.Store(key, usage{size: size, latest: latest})
+	}
+	return size, latest
+}
+
+// diskUsage returns the total size of the files under path and the latest
+// modification time among them.
+func diskUsage(path string) (int64, time.Time) {
+	var size int64
+	var latest time.Time
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			size += info.Size()
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return size, latest
+}
+
+// WorktreeChanges summarizes the work in a worktree that removing it would
+// lose.
+type WorktreeChanges struct {
+	Modified     int // tracked files with uncommitted changes
+	Untracked    int
+	Unreferenced int // commits reachable from HEAD but from no ref
+}
+
+// Clean reports whether the worktree can be removed without losing work.
+func (c WorktreeChanges) Clean() bool {
+	return c == WorktreeChanges{}
+}
+
+func (c WorktreeChanges) String() string {
+	var parts []string
+	if c.Modified > 0 {
+		parts = append(parts, fmt.Sprintf("%d modified", c.Modified))
+	}
+	if c.Untracked > 0 {
+		parts = append(parts, fmt.Sprintf("%d untracked", c.Untracked))
+	}
+	if c.Unreferenced > 0 {
+		parts = append(parts, fmt.Sprintf("%d unreferenced commits", c.Unreferenced))
+	}
+	if len(parts) == 0 {
+		return "clean"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// InspectWorktree reports the uncommitted changes, untracked files and
+// commits not on any ref in the worktree at path.
+func InspectWorktree(path string) (WorktreeChanges, error) {
+	var c WorktreeChanges
+	out, err := exec.Command("git", "-C", path, "status", "--porcelain", "--untracked-files=all").Output()
+	if err != nil {
+		return c, fmt.Errorf("status %s: %w", path, err)
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "??"):
+			c.Untracked++
+		default:
+			c.Modified++
+		}
+	}
+	out, err = exec.Command("git", "-C", path, "rev-list", "--count", "HEAD", "--not", "--glob=refs/*").Output()
+	if err != nil {
+		return c, fmt.Errorf("rev-list %s: %w", path, err)
+	}
+	c.Unreferenced, _ = strconv.Atoi(strings.TrimSpace(string(out)))
+	return c, nil
+}
+
+// SnapshotWorktree records the worktree's files, including untracked ones,
+// as a commit on top of HEAD without touching the worktree or its index.
+func SnapshotWorktree(path, message string) (string, error) {
+	dir, err := os.MkdirTemp("", "gh-review-index")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+	env := append(os.Environ(), "GIT_INDEX_FILE="+filepath.Join(dir, "index"))
+	gitCmd := func(args ...string) (string, error) {
+		cmd := exec.Command("git", append([]string{"-C", path}, args...)...)
+		cmd.Env = env
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("snapshot %s: git %s: %w", path, args[0], err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+	if _, err := gitCmd("read-tree", "HEAD"); err != nil {
+		return "", err
+	}
+	if _, err := gitCmd("add", "-A"); err != nil {
+		return "", err
+	}
+	tree, err := gitCmd("write-tree")
+	if err != nil {
+		return "", err
+	}
+	return gitCmd("commit-tree", tree, "-p", "HEAD", "-m", message)
+}
+
+// SaveWorktreeRef snapshots the worktree of a PR under
+// refs/gh-review/saved/pr-<n>/<timestamp> and returns the ref name.
+func SaveWorktreeRef(repoRoot, path string, prNumber int, now time.Time) (string, error) {
+	sha, err := SnapshotWorktree(path, fmt.Sprintf("gh-review: saved worktree of PR #%d", prNumber))
+	if err != nil {
+		return "", err
+	}
+	ref := fmt.Sprintf("refs/gh-review/saved/pr-%d/%s", prNumber, now.Format("20060102-150405"))
+	if err := exec.Command("git", "-C", repoRoot, "update-ref", ref, sha).Run(); err != nil {
+		return "", fmt.Errorf("update-ref %s: %w", ref, err)
+	}
+	return ref, nil
+}
+
+// SaveWorktreePatch writes the worktree's changes as a patch under the git
+// directory and returns its path. Local commits are included when the
+// worktree is based on the last fetched PR head.
+func SaveWorktreePatch(repoRoot, path string, prNumber int, now time.Time) (string, error) {
+	sha, err := SnapshotWorktree(path, fmt.Sprintf("gh-review: patch of PR #%d", prNumber))
+	if err != nil {
+		return "", err
+	}
+	base := "HEAD"
+	if exec.Command("git", "-C", path, "merge-base", "--is-ancestor", PRRef(prNumber), "HEAD").Run() == nil {
+		base = PRRef(prNumber)
+	}
+	patch, err := exec.Command("git", "-C", path, "diff", "--binary", base, sha).Output()
+	if err != nil {
+		return "", fmt.Errorf("diff %s: %w", path, err)
+	}
+	out, err := exec.Command("git", "-C", repoRoot, "rev-parse", "--path-format=absolute", "--git-common-dir").Output()
+	if err != nil {
+		return "", fmt.Errorf("locate git dir: %w", err)
+	}
+	dir := filepath.Join(strings.TrimSpace(string(out)), "gh-review", "patches")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	file := filepath.Join(dir, fmt.Sprintf("pr-%d-%s.patch", prNumber, now.Format("20060102-150405")))
+	if err := os.WriteFile(file, patch, 0o644); err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+// RestoreWorktree recreates a removed worktree at path, detached at sha.
+func RestoreWorktree(repoRoot, path, sha string) error {
+	if out, err := exec.Command("git", "-C", repoRoot, "worktree", "add", "--detach", path, sha).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to restore worktree at %s: %w: %s", path, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// RemoveWorktreeAt removes the git worktree at path, discarding any changes.
+func RemoveWorktreeAt(repoRoot, path string) error {
+	if err := exec.Command("git", "-C", repoRoot, "worktree", "remove", "--force", path).Run(); err != nil {
+		return fmt.Errorf("failed to remove worktree at %s: %w", path, err)
+	}
+	return nil
+}
+
+// WorktreeHead returns the commit checked out in the worktree at path.
+func WorktreeHead(path string) (string, error) {
+	return revParse(path, "HEAD")
+}
+
+var (
+	// ErrWorktreeDirty is returned when an update would overwrite local changes.
+	ErrWorktreeDirty = errors.New("worktree has uncommitted changes; commit, stash or discard them first")
+	// ErrWorktreeOnBranch is returned when the worktree is not detached.
+	ErrWorktreeOnBranch = errors.New("worktree is on a branch; update it with git pull")
+	// ErrWorktreeDiverged is returned when the worktree has commits that the
+	// new PR head does not contain.
+	ErrWorktreeDiverged = errors.New("worktree has local commits not in the PR head")
+)
+
+// WorktreeUpdate describes the result of UpdateWorktree.
+type WorktreeUpdate struct {
+	Old, New    string
+	Commits     int  // commits in New that were not in Old
+	ForcePushed bool // New does not contain Old; the worktree was reset
+}
+
+// UpToDate reports whether the worktree was already at the PR head.
+func (u WorktreeUpdate) UpToDate() bool { return u.Old == u.New }
+
+// UpdateWorktree fetches the PR's latest head and moves the detached worktree
+// to it. It refuses when the worktree has uncommitted changes, is on a
+// branch, or has commits of its own that the move would drop. A force-pushed
+// PR is reset as long as the worktree was still at the previously fetched head.
+func UpdateWorktree(repoRoot, path string, prNumber int) (WorktreeUpdate, error) {
+	old, err := WorktreeHead(path)
+	if err != nil {
+		return WorktreeUpdate{}, err
+	}
+	if exec.Command("git", "-C", path, "symbolic-ref", "-q", "HEAD").Run() == nil {
+		return WorktreeUpdate{}, ErrWorktreeOnBranch
+	}
+	if dirty, err := IsDirty(path); err != nil {
+		return WorktreeUpdate{}, err
+	} else if dirty {
+		return WorktreeUpdate{}, ErrWorktreeDirty
+	}
+	prev, _ := revParse(repoRoot, PRRef(prNumber))
+	sha, err := fetchPRHead(repoRoot, prNumber)
+	if err != nil {
+		return WorktreeUpdate{}, err
+	}
+	u := WorktreeUpdate{Old: old, New: sha}
+	if u.UpToDate() {
+		return u, nil
+	}
+	u.ForcePushed = exec.Command("git", "-C", repoRoot, "merge-base", "--is-ancestor", old, sha).Run() != nil
+	if u.ForcePushed && old != prev {
+		return WorktreeUpdate{}, ErrWorktreeDiverged
+	}
+	out, err := exec.Command("git", "-C", repoRoot, "rev-list", "--count", old+".."+sha).Output()
+	if err != nil {
+		return WorktreeUpdate{}, fmt.Errorf("count commits: %w", err)
+	}
+	u.Commits, _ = strconv.Atoi(strings.TrimSpace(string(out)))
+	if out, err := exec.Command("git", "-C", path, "checkout", "-q", "--detach", sha).CombinedOutput(); err != nil {
+		return WorktreeUpdate{}, fmt.Errorf("checkout %s: %w: %s", ShortSHA(sha), err, strings.TrimSpace(string(out)))
+	}
+	return u, nil
+}
+
+// ShortSHA abbreviates a commit SHA for display.
+func ShortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
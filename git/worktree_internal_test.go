@@ -0,0 +1,88 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseWorktreeList(t *testing.T) {
+	out := `worktree /repo
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /repo/.worktrees/pr-1
+HEAD 2222222222222222222222222222222222222222
+detached
+locked reviewing on laptop
+
+worktree /repo/.worktrees/pr-12
+HEAD 3333333333333333333333333333333333333333
+branch refs/heads/feature/login
+prunable gitdir file points to non-existent location
+
+`
+	want := []Worktree{
+		{Path: "/repo", HEAD: "1111111111111111111111111111111111111111", Branch: "main"},
+		{Path: "/repo/.worktrees/pr-1", HEAD: "2222222222222222222222222222222222222222", Detached: true, Locked: true},
+		{Path: "/repo/.worktrees/pr-12", HEAD: "3333333333333333333333333333333333333333", Branch: "feature/login", Prunable: true},
+	}
+	if got := parseWorktreeList(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWorktreeList() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseDiff(t *testing.T) {
+	out := `diff --git a/img.png b/img.png
+new file mode 100644
+index 0000000..1111111
+Binary files /dev/null and b/img.png differ
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+index 2222222..0000000
+--- a/old.txt
++++ /dev/null
+@@ -1,2 +0,0 @@
+-a
+-b
+`
+	want := []FileDiff{
+		{Filename: "img.png", Binary: true},
+		{Filename: "old.txt", Patch: "@@ -1,2 +0,0 @@\n-a\n-b", Deletions: 2},
+	}
+	if got := parseDiff(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDiff() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCachedDiskUsage(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a"), []byte("1234"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wt := Worktree{Path: dir, HEAD: "1111"}
+	if size, _ := cachedDiskUsage(wt, false); size != 4 {
+		t.Fatalf("cachedDiskUsage() = %d, want 4", size)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b"), []byte("5678"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		wt    Worktree
+		dirty bool
+		want  int64
+	}{
+		{"同じHEADなら再利用", wt, false, 4},
+		{"変更があれば数え直す", wt, true, 8},
+		{"HEADが動けば数え直す", Worktree{Path: dir, HEAD: "2222"}, false, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if size, _ := cachedDiskUsage(tt.wt, tt.dirty); size != tt.want {
+				t.Errorf("cachedDiskUsage() = %d, want %d", size, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package git_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/git"
+)
+
+// initRepo creates a repository with one commit and returns its root.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	run(t, root, "init", "-q", "-b", "main")
+	run(t, root, "config", "user.email", "test@example.com")
+	run(t, root, "config", "user.name", "test")
+	writeFile(t, filepath.Join(root, "README.md"), "hello\n")
+	run(t, root, "add", ".")
+	run(t, root, "commit", "-q", "-m", "init")
+	return root
+}
+
+func run(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return string(out)
+}
+
+func writeFile(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestManagedWorktrees(t *testing.T) {
+	root := initRepo(t)
+	wt := wtPath(root, 7)
+	run(t, root, "worktree", "add", "-q", "--detach", wt, "HEAD")
+	run(t, root, "worktree", "add", "-q", "-b", "other", filepath.Join(root, "..", filepath.Base(root)+"-other"))
+
+	wts, err := git.ManagedWorktrees(root, git.DefaultLayout())
+	if err != nil {
+		t.Fatalf("ManagedWorktrees() error = %v", err)
+	}
+	if len(wts) != 1 {
+		t.Fatalf("ManagedWorktrees() = %+v, want only the .worktrees entry", wts)
+	}
+	got := wts[0]
+	if got.Path != wt || got.PRNumber != 7 || !got.Detached || got.Dirty {
+		t.Errorf("worktree = %+v", got)
+	}
+	if got.DiskUsage == 0 || got.LastUsed.IsZero() {
+		t.Errorf("DiskUsage/LastUsed not set: %+v", got)
+	}
+
+	writeFile(t, filepath.Join(wt, "scratch.txt"), "wip\n")
+	wts, err = git.ManagedWorktrees(root, git.DefaultLayout())
+	if err != nil {
+		t.Fatalf("ManagedWorktrees() error = %v", err)
+	}
+	if !wts[0].Dirty {
+		t.Error("Dirty = false after adding an untracked file")
+	}
+
+	if err := git.RemoveWorktreeAt(root, wt); err != nil {
+		t.Fatalf("RemoveWorktreeAt() error = %v", err)
+	}
+	if _, err := os.Stat(wt); !os.IsNotExist(err) {
+		t.Errorf("worktree directory still exists: %v", err)
+	}
+}
+
+// initOrigin creates an origin repository exposing refs/pull/1/head and a
+// clone of it, returning both roots.
+func initOrigin(t *testing.T) (origin, clone string) {
+	t.Helper()
+	origin = initRepo(t)
+	run(t, origin, "update-ref", "refs/pull/1/head", "HEAD")
+	clone = filepath.Join(t.TempDir(), "clone")
+	if out, err := exec.Command("git", "clone", "-q", origin, clone).CombinedOutput(); err != nil {
+		t.Fatalf("clone: %v\n%s", err, out)
+	}
+	run(t, clone, "config", "user.email", "test@example.com")
+	run(t, clone, "config", "user.name", "test")
+	return origin, clone
+}
+
+// pushPR commits a change to the origin and points refs/pull/1/head at it.
+func pushPR(t *testing.T, origin, name string) string {
+	t.Helper()
+	writeFile(t, filepath.Join(origin, name), name+"\n")
+	run(t, origin, "add", ".")
+	run(t, origin, "commit", "-q", "-m", name)
+	run(t, origin, "update-ref", "refs/pull/1/head", "HEAD")
+	return strings.TrimSpace(run(t, origin, "rev-parse", "HEAD"))
+}
+
+func TestUpdateWorktree(t *testing.T) {
+	origin, clone := initOrigin(t)
+	if err := git.CreateWorktree(clone, wtPath(clone, 1), 1); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	wt := wtPath(clone, 1)
+	old, _ := git.WorktreeHead(wt)
+
+	u, err := git.UpdateWorktree(clone, wtPath(clone, 1), 1)
+	if err != nil || !u.UpToDate() {
+		t.Fatalf("UpdateWorktree() = %+v, %v; want up to date", u, err)
+	}
+
+	pushPR(t, origin, "a.txt")
+	want := pushPR(t, origin, "b.txt")
+	u, err = git.UpdateWorktree(clone, wtPath(clone, 1), 1)
+	if err != nil {
+		t.Fatalf("UpdateWorktree() error = %v", err)
+	}
+	if u.Old != old || u.New != want || u.Commits != 2 || u.ForcePushed {
+		t.Errorf("UpdateWorktree() = %+v, want %s..%s with 2 commits", u, old, want)
+	}
+	if head, _ := git.WorktreeHead(wt); head != want {
+		t.Errorf("WorktreeHead() = %s, want %s", head, want)
+	}
+
+	// Force-push: the worktree was at the previously fetched head, so reset.
+	run(t, origin, "reset", "-q", "--hard", "HEAD~1")
+	want = pushPR(t, origin, "c.txt")
+	u, err = git.UpdateWorktree(clone, wtPath(clone, 1), 1)
+	if err != nil || !u.ForcePushed || u.New != want {
+		t.Fatalf("UpdateWorktree() after force-push = %+v, %v", u, err)
+	}
+}
+
+func TestUpdateWorktree_Refuses(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, wt string)
+		want    error
+	}{
+		{"未コミットの変更", func(t *testing.T, wt string) {
+			writeFile(t, filepath.Join(wt, "README.md"), "edited\n")
+		}, git.ErrWorktreeDirty},
+		{"未追跡ファイル", func(t *testing.T, wt string) {
+			writeFile(t, filepath.Join(wt, "new.txt"), "new\n")
+		}, git.ErrWorktreeDirty},
+		{"ローカルコミット", func(t *testing.T, wt string) {
+			writeFile(t, filepath.Join(wt, "local.txt"), "local\n")
+			run(t, wt, "add", ".")
+			run(t, wt, "commit", "-q", "-m", "local")
+		}, git.ErrWorktreeDiverged},
+		{"ブランチ上", func(t *testing.T, wt string) {
+			run(t, wt, "switch", "-q", "-c", "fix")
+		}, git.ErrWorktreeOnBranch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origin, clone := initOrigin(t)
+			if err := git.CreateWorktree(clone, wtPath(clone, 1), 1); err != nil {
+				t.Fatalf("CreateWorktree() error = %v", err)
+			}
+			wt := wtPath(clone, 1)
+			tt.prepare(t, wt)
+			before, _ := git.WorktreeHead(wt)
+			pushPR(t, origin, "next.txt")
+
+			if _, err := git.UpdateWorktree(clone, wtPath(clone, 1), 1); !errors.Is(err, tt.want) {
+				t.Fatalf("UpdateWorktree() error = %v, want %v", err, tt.want)
+			}
+			if head, _ := git.WorktreeHead(wt); head != before {
+				t.Errorf("worktree moved from %s to %s", before, head)
+			}
+		})
+	}
+}
+
+func TestInspectWorktree(t *testing.T) {
+	_, clone := initOrigin(t)
+	if err := git.CreateWorktree(clone, wtPath(clone, 1), 1); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	wt := wtPath(clone, 1)
+	if c, err := git.InspectWorktree(wt); err != nil || !c.Clean() {
+		t.Fatalf("InspectWorktree() = %+v, %v; want clean", c, err)
+	}
+
+	writeFile(t, filepath.Join(wt, "local.txt"), "local\n")
+	run(t, wt, "add", ".")
+	run(t, wt, "commit", "-q", "-m", "local")
+	writeFile(t, filepath.Join(wt, "README.md"), "edited\n")
+	writeFile(t, filepath.Join(wt, "a.txt"), "a\n")
+	writeFile(t, filepath.Join(wt, "b.txt"), "b\n")
+
+	c, err := git.InspectWorktree(wt)
+	if err != nil {
+		t.Fatalf("InspectWorktree() error = %v", err)
+	}
+	want := git.WorktreeChanges{Modified: 1, Untracked: 2, Unreferenced: 1}
+	if c != want {
+		t.Errorf("InspectWorktree() = %+v, want %+v", c, want)
+	}
+}
+
+func TestSaveAndRestoreWorktree(t *testing.T) {
+	_, clone := initOrigin(t)
+	if err := git.CreateWorktree(clone, wtPath(clone, 1), 1); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	wt := wtPath(clone, 1)
+	writeFile(t, filepath.Join(wt, "local.txt"), "local\n")
+	run(t, wt, "add", ".")
+	run(t, wt, "commit", "-q", "-m", "local")
+	writeFile(t, filepath.Join(wt, "README.md"), "edited\n")
+	writeFile(t, filepath.Join(wt, "new.txt"), "new\n")
+	head, _ := git.WorktreeHead(wt)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	ref, err := git.SaveWorktreeRef(clone, wt, 1, now)
+	if err != nil {
+		t.Fatalf("SaveWorktreeRef() error = %v", err)
+	}
+	if ref != "refs/gh-review/saved/pr-1/20260102-030405" {
+		t.Errorf("SaveWorktreeRef() = %q", ref)
+	}
+	if got := run(t, clone, "show", ref+":new.txt"); got != "new\n" {
+		t.Errorf("saved new.txt = %q", got)
+	}
+	if got := strings.TrimSpace(run(t, clone, "rev-parse", ref+"^")); got != head {
+		t.Errorf("saved ref parent = %s, want HEAD %s", got, head)
+	}
+	if st := run(t, wt, "status", "--porcelain"); !strings.Contains(st, " M README.md") || !strings.Contains(st, "?? new.txt") {
+		t.Errorf("worktree status changed by save:\n%s", st)
+	}
+
+	file, err := git.SaveWorktreePatch(clone, wt, 1, now)
+	if err != nil {
+		t.Fatalf("SaveWorktreePatch() error = %v", err)
+	}
+	body, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"local.txt", "README.md", "new.txt"} {
+		if !strings.Contains(string(body), "b/"+name) {
+			t.Errorf("patch does not contain %s:\n%s", name, body)
+		}
+	}
+
+	if err := git.RemoveWorktreeAt(clone, wt); err != nil {
+		t.Fatalf("RemoveWorktreeAt() error = %v", err)
+	}
+	if err := git.RestoreWorktree(clone, wt, head); err != nil {
+		t.Fatalf("RestoreWorktree() error = %v", err)
+	}
+	if got, _ := git.WorktreeHead(wt); got != head {
+		t.Errorf("restored HEAD = %s, want %s", got, head)
+	}
+}
@@ -3,12 +3,15 @@ package github
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	gogithub "github.com/google/go-github/v68/github"
 	"github.com/kosuke9809/gh-review/model"
 	"golang.org/x/oauth2"
+	"golang.org/x/sync/errgroup"
 )
 
 // NewClient creates an authenticated go-github client.
@@ -61,6 +64,18 @@ func FetchPRs(ctx context.Context, client *gogithub.Client, owner, repo, current
 	return result, nil
 }
 
+// FetchPRState returns "open", "closed" or "merged" for a PR.
+func FetchPRState(ctx context.Context, client *gogithub.Client, owner, repo string, prNumber int) (string, error) {
+	pr, _, err := client.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return "", fmt.Errorf("get PR #%d: %w", prNumber, err)
+	}
+	if pr.GetMerged() {
+		return "merged", nil
+	}
+	return pr.GetState(), nil
+}
+
 // IsReviewRequested reports whether currentUser is in the PR's requested reviewers.
 func IsReviewRequested(pr *gogithub.PullRequest, currentUser string) bool {
 	for _, r := range pr.RequestedReviewers {
@@ -73,17 +88,24 @@ func IsReviewRequested(pr *gogithub.PullRequest, currentUser string) bool {
 
 // FetchReviews fetches all reviews for a PR.
 func FetchReviews(ctx context.Context, client *gogithub.Client, owner, repo string, prNumber int) ([]model.Review, error) {
-	reviews, _, err := client.PullRequests.ListReviews(ctx, owner, repo, prNumber, nil)
-	if err != nil {
-		return nil, err
-	}
+	opts := &gogithub.ListOptions{PerPage: 100}
 	var result []model.Review
-	for _, r := range reviews {
-		result = append(result, model.Review{
-			Author:    r.GetUser().GetLogin(),
-			State:     r.GetState(),
-			CreatedAt: r.GetSubmittedAt().Time,
-		})
+	for {
+		reviews, resp, err := client.PullRequests.ListReviews(ctx, owner, repo, prNumber, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range reviews {
+			result = append(result, model.Review{
+				Author:    r.GetUser().GetLogin(),
+				State:     r.GetState(),
+				CreatedAt: r.GetSubmittedAt().Time,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
 	}
 	return result, nil
 }
@@ -139,6 +161,194 @@ func FetchComments(ctx context.Context, client *gogithub.Client, owner, repo str
 	return result, nil
 }
 
+// mentionREs caches the @-mention pattern of each user.
+var mentionREs sync.Map
+
+// Mentions reports whether body @-mentions user.
+func Mentions(body, user string) bool {
+	re, ok := mentionREs.Load(user)
+	if !ok {
+		re, _ = mentionREs.LoadOrStore(user, regexp.MustCompile(`(?i)(^|[^\w@])@`+regexp.QuoteMeta(user)+`([^\w-]|$)`))
+	}
+	return re.(*regexp.Regexp).MatchString(body)
+}
+
+// MentionedSince reports whether user was mentioned in an issue or review
+// comment on the PR created or edited after since.
+func MentionedSince(ctx context.Context, client *gogithub.Client, owner, repo string, prNumber int, user string, since time.Time) (bool, error) {
+	issueOpts := &gogithub.IssueListCommentsOptions{Since: &since, ListOptions: gogithub.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := client.Issues.ListComments(ctx, owner, repo, prNumber, issueOpts)
+		if err != nil {
+			return false, err
+		}
+		for _, c := range comments {
+			if Mentions(c.GetBody(), user) {
+				return true, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		issueOpts.Page = resp.NextPage
+	}
+	reviewOpts := &gogithub.PullRequestListCommentsOptions{Since: since, ListOptions: gogithub.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := client.PullRequests.ListComments(ctx, owner, repo, prNumber, reviewOpts)
+		if err != nil {
+			return false, err
+		}
+		for _, c := range comments {
+			if Mentions(c.GetBody(), user) {
+				return true, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		reviewOpts.Page = resp.NextPage
+	}
+	return false, nil
+}
+
+// FetchRequestedAt returns when user's review was last requested on the PR,
+// from the issue timeline. It returns the zero time when no such event exists.
+func FetchRequestedAt(ctx context.Context, client *gogithub.Client, owner, repo string, prNumber int, user string) (time.Time, error) {
+	opts := &gogithub.ListOptions{PerPage: 100}
+	var latest time.Time
+	for {
+		events, resp, err := client.Issues.ListIssueTimeline(ctx, owner, repo, prNumber, opts)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("list timeline: %w", err)
+		}
+		if t := LastReviewRequest(events, user); t.After(latest) {
+			latest = t
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return latest, nil
+}
+
+// FetchReviewRequests returns when each reviewer's review was last requested
+// on the PR, from the issue timeline, keyed by model.ReviewerKey.
+func FetchReviewRequests(ctx context.Context, client *gogithub.Client, owner, repo string, prNumber int) (map[string]time.Time, error) {
+	opts := &gogithub.ListOptions{PerPage: 100}
+	var events []*gogithub.Timeline
+	for {
+		page, resp, err := client.Issues.ListIssueTimeline(ctx, owner, repo, prNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("list timeline: %w", err)
+		}
+		events = append(events, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return ReviewRequests(events), nil
+}
+
+// ReviewRequests returns the time of the latest review_requested event of
+// each requested user or team, keyed by model.ReviewerKey.
+func ReviewRequests(events []*gogithub.Timeline) map[string]time.Time {
+	latest := make(map[string]time.Time)
+	for _, e := range events {
+		if e.GetEvent() != "review_requested" {
+			continue
+		}
+		key := model.ReviewerKey(e.GetReviewer().GetLogin(), false)
+		if e.RequestedTeam != nil {
+			key = model.ReviewerKey(e.GetRequestedTeam().GetSlug(), true)
+		}
+		if t := e.GetCreatedAt().Time; t.After(latest[key]) {
+			latest[key] = t
+		}
+	}
+	return latest
+}
+
+// LastReviewRequest returns the time of the latest review_requested event
+// naming user as the reviewer.
+func LastReviewRequest(events []*gogithub.Timeline, user string) time.Time {
+	var latest time.Time
+	for _, e := range events {
+		if e.GetEvent() != "review_requested" || !strings.EqualFold(e.GetReviewer().GetLogin(), user) {
+			continue
+		}
+		if t := e.GetCreatedAt().Time; t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+// FetchReviewActivity returns the PRs in the repo that user reviewed and that
+// were updated since the given time, with user's reviews, the time user was
+// requested and the PR's size. It uses the search API, so open, closed and
+// merged PRs are all included. Activities in known, keyed by PR number, are
+// reused for the PRs that have not been updated since they were fetched.
+func FetchReviewActivity(ctx context.Context, client *gogithub.Client, owner, repo, user string, since time.Time, known map[int]model.ReviewActivity) ([]model.ReviewActivity, error) {
+	query := fmt.Sprintf("repo:%s/%s is:pr reviewed-by:%s -author:%s updated:>=%s",
+		owner, repo, user, user, since.Format("2006-01-02"))
+	opts := &gogithub.SearchOptions{Sort: "updated", ListOptions: gogithub.ListOptions{PerPage: 100}}
+	var issues []*gogithub.Issue
+	for {
+		res, resp, err := client.Search.Issues(ctx, query, opts)
+		if err != nil {
+			return nil, fmt.Errorf("search reviewed PRs: %w", err)
+		}
+		issues = append(issues, res.Issues...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	acts := make([]model.ReviewActivity, len(issues))
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(4)
+	for i, issue := range issues {
+		acts[i] = model.ReviewActivity{
+			Number:    issue.GetNumber(),
+			Title:     issue.GetTitle(),
+			CreatedAt: issue.GetCreatedAt().Time,
+			UpdatedAt: issue.GetUpdatedAt().Time,
+		}
+		if k, ok := known[acts[i].Number]; ok && k.UpdatedAt.Equal(acts[i].UpdatedAt) {
+			acts[i] = k
+			continue
+		}
+		eg.Go(func() error {
+			a := &acts[i]
+			reviews, err := FetchReviews(ctx, client, owner, repo, a.Number)
+			if err != nil {
+				return err
+			}
+			for _, r := range reviews {
+				if r.Author == user {
+					a.Reviews = append(a.Reviews, r)
+				}
+			}
+			if a.RequestedAt, err = FetchRequestedAt(ctx, client, owner, repo, a.Number, user); err != nil {
+				return err
+			}
+			pr, _, err := client.PullRequests.Get(ctx, owner, repo, a.Number)
+			if err != nil {
+				return err
+			}
+			a.Lines = pr.GetAdditions() + pr.GetDeletions()
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return acts, nil
+}
+
 // FetchDiff fetches the diff files for a PR.
 func FetchDiff(ctx context.Context, client *gogithub.Client, owner, repo string, prNumber int) ([]model.DiffFile, error) {
 	files, _, err := client.PullRequests.ListFiles(ctx, owner, repo, prNumber, nil)
@@ -149,6 +359,7 @@ func FetchDiff(ctx context.Context, client *gogithub.Client, owner, repo string,
 	for _, f := range files {
 		result = append(result, model.DiffFile{
 			Filename:  f.GetFilename(),
+			Previous:  f.GetPreviousFilename(),
 			Patch:     f.GetPatch(),
 			Additions: int(f.GetAdditions()),
 			Deletions: int(f.GetDeletions()),
@@ -157,6 +368,28 @@ func FetchDiff(ctx context.Context, client *gogithub.Client, owner, repo string,
 	return result, nil
 }
 
+// FetchFileContent fetches the content of path at ref.
+func FetchFileContent(ctx context.Context, client *gogithub.Client, owner, repo, path, ref string) (string, error) {
+	fc, _, _, err := client.Repositories.GetContents(ctx, owner, repo, path, &gogithub.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return "", fmt.Errorf("get %s at %s: %w", path, ref, err)
+	}
+	if fc == nil {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+	return fc.GetContent()
+}
+
+// FetchMergeBase returns the merge base of base and head, the commit a PR's
+// patches are relative to.
+func FetchMergeBase(ctx context.Context, client *gogithub.Client, owner, repo, base, head string) (string, error) {
+	cmp, _, err := client.Repositories.CompareCommits(ctx, owner, repo, base, head, &gogithub.ListOptions{PerPage: 1})
+	if err != nil {
+		return "", fmt.Errorf("compare %s...%s: %w", base, head, err)
+	}
+	return cmp.GetMergeBaseCommit().GetSHA(), nil
+}
+
 // CalcReviewState determines the review state for the given user based on reviews and PR updated time.
 func CalcReviewState(currentUser string, reviews []model.Review, prUpdatedAt time.Time) model.ReviewState {
 	if len(reviews) == 0 {
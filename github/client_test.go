@@ -1,9 +1,15 @@
 package github_test
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
+	gogithub "github.com/google/go-github/v68/github"
 	"github.com/kosuke9809/gh-review/github"
 	"github.com/kosuke9809/gh-review/model"
 )
@@ -85,3 +91,140 @@ func TestCalcReviewState(t *testing.T) {
 		})
 	}
 }
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		body string
+		want bool
+	}{
+		{"@me please take a look", true},
+		{"cc @ME.", true},
+		{"thanks (@me)", true},
+		{"@meow is unrelated", false},
+		{"@me-team is a team", false},
+		{"mail me@me.com", false},
+		{"no mention", false},
+	}
+	for _, tt := range tests {
+		if got := github.Mentions(tt.body, "me"); got != tt.want {
+			t.Errorf("Mentions(%q) = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
+
+// newTestClient returns a client talking to a test server serving mux.
+func newTestClient(t *testing.T, mux *http.ServeMux) *gogithub.Client {
+	t.Helper()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	client := gogithub.NewClient(nil)
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+	return client
+}
+
+// servePages serves bodies as the pages of a paginated list at path.
+func servePages(mux *http.ServeMux, path string, bodies ...string) {
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		fmt.Sscan(r.URL.Query().Get("page"), &page)
+		if page < len(bodies) {
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=%d>; rel="next"`, path, page+1))
+		}
+		fmt.Fprint(w, bodies[page-1])
+	})
+}
+
+func TestMentionedSince_Paginates(t *testing.T) {
+	mux := http.NewServeMux()
+	servePages(mux, "/repos/o/r/issues/1/comments", `[{"body":"lgtm"}]`, `[{"body":"cc @me"}]`)
+	servePages(mux, "/repos/o/r/pulls/1/comments", `[]`)
+	client := newTestClient(t, mux)
+
+	got, err := github.MentionedSince(context.Background(), client, "o", "r", 1, "me", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("MentionedSince() error = %v", err)
+	}
+	if !got {
+		t.Error("MentionedSince() = false, want the mention on the second page found")
+	}
+}
+
+func TestLastReviewRequest(t *testing.T) {
+	first := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	again := first.Add(48 * time.Hour)
+	event := func(kind, reviewer string, at time.Time) *gogithub.Timeline {
+		return &gogithub.Timeline{
+			Event:     gogithub.Ptr(kind),
+			Reviewer:  &gogithub.User{Login: gogithub.Ptr(reviewer)},
+			CreatedAt: &gogithub.Timestamp{Time: at},
+		}
+	}
+	events := []*gogithub.Timeline{
+		event("review_requested", "me", first),
+		event("review_requested", "other", again.Add(time.Hour)),
+		event("reviewed", "me", first.Add(time.Hour)),
+		event("review_requested", "me", again),
+	}
+	if got := github.LastReviewRequest(events, "me"); !got.Equal(again) {
+		t.Errorf("LastReviewRequest() = %v, want %v", got, again)
+	}
+	if got := github.LastReviewRequest(events, "nobody"); !got.IsZero() {
+		t.Errorf("LastReviewRequest(nobody) = %v, want zero", got)
+	}
+}
+
+func TestReviewRequests(t *testing.T) {
+	first := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	again := first.Add(48 * time.Hour)
+	events := []*gogithub.Timeline{
+		{Event: gogithub.Ptr("review_requested"), Reviewer: &gogithub.User{Login: gogithub.Ptr("alice")}, CreatedAt: &gogithub.Timestamp{Time: first}},
+		{Event: gogithub.Ptr("review_requested"), RequestedTeam: &gogithub.Team{Slug: gogithub.Ptr("core")}, CreatedAt: &gogithub.Timestamp{Time: first}},
+		{Event: gogithub.Ptr("reviewed"), Reviewer: &gogithub.User{Login: gogithub.Ptr("alice")}, CreatedAt: &gogithub.Timestamp{Time: again.Add(time.Hour)}},
+		{Event: gogithub.Ptr("review_requested"), Reviewer: &gogithub.User{Login: gogithub.Ptr("alice")}, CreatedAt: &gogithub.Timestamp{Time: again}},
+	}
+	got := github.ReviewRequests(events)
+	want := map[string]time.Time{"alice": again, "team:core": first}
+	if len(got) != len(want) {
+		t.Fatalf("ReviewRequests() = %v, want %v", got, want)
+	}
+	for k, w := range want {
+		if !got[k].Equal(w) {
+			t.Errorf("ReviewRequests()[%q] = %v, want %v", k, got[k], w)
+		}
+	}
+}
+
+func TestFetchReviewActivity(t *testing.T) {
+	updated := "2024-05-02T00:00:00Z"
+	mux := http.NewServeMux()
+	servePages(mux, "/search/issues",
+		`{"items":[{"number":1,"updated_at":"`+updated+`"}]}`,
+		`{"items":[{"number":2,"updated_at":"`+updated+`"}]}`)
+	servePages(mux, "/repos/o/r/pulls/2/reviews",
+		`[{"user":{"login":"bob"},"state":"APPROVED"}]`,
+		`[{"user":{"login":"me"},"state":"APPROVED","submitted_at":"2024-05-01T00:00:00Z"}]`)
+	servePages(mux, "/repos/o/r/issues/2/timeline", `[]`)
+	mux.HandleFunc("/repos/o/r/pulls/2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"additions":3,"deletions":1}`)
+	})
+	mux.HandleFunc("/repos/o/r/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("reviews of unchanged #1 fetched again")
+	})
+	client := newTestClient(t, mux)
+	at, _ := time.Parse(time.RFC3339, updated)
+	known := map[int]model.ReviewActivity{1: {Number: 1, UpdatedAt: at, Lines: 7}}
+
+	got, err := github.FetchReviewActivity(context.Background(), client, "o", "r", "me", at.AddDate(0, 0, -7), known)
+	if err != nil {
+		t.Fatalf("FetchReviewActivity() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("FetchReviewActivity() = %+v, want both search pages", got)
+	}
+	if got[0].Lines != 7 {
+		t.Errorf("#1 = %+v, want the known activity reused", got[0])
+	}
+	if len(got[1].Reviews) != 1 || got[1].Lines != 4 {
+		t.Errorf("#2 = %+v, want my review from the second page and 4 lines", got[1])
+	}
+}
@@ -0,0 +1,208 @@
+// Package gotest finds the Go packages affected by a change and runs their
+// tests.
+package gotest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Package is a package of the module, as reported by `go list`.
+type Package struct {
+	ImportPath   string
+	Dir          string
+	Imports      []string
+	TestImports  []string
+	XTestImports []string
+}
+
+// ListPackages lists the packages of the module or workspace at dir.
+func ListPackages(ctx context.Context, dir string) ([]Package, error) {
+	cmd := exec.CommandContext(ctx, "go", "list", "-e", "-json=ImportPath,Dir,Imports,TestImports,XTestImports", "./...")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %s", strings.TrimSpace(stderr.String()))
+	}
+	var pkgs []Package
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var p Package
+		if err := dec.Decode(&p); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("go list: %w", err)
+		}
+		pkgs = append(pkgs, p)
+	}
+	return pkgs, nil
+}
+
+// Affected returns the import paths of the packages whose tests a change to
+// files (relative to root) may affect: the packages containing the files and
+// the packages that import them, directly or indirectly. A package whose
+// tests import an affected package is affected too. A change to go.mod or
+// go.sum affects every package.
+func Affected(pkgs []Package, root string, files []string) []string {
+	byDir := make(map[string]string, len(pkgs))
+	rdeps := make(map[string][]string)
+	for _, p := range pkgs {
+		if rel, err := filepath.Rel(root, p.Dir); err == nil {
+			byDir[rel] = p.ImportPath
+		}
+		for _, imp := range p.Imports {
+			rdeps[imp] = append(rdeps[imp], p.ImportPath)
+		}
+	}
+
+	affected := make(map[string]bool)
+	var queue []string
+	mark := func(path string) {
+		if !affected[path] {
+			affected[path] = true
+			queue = append(queue, path)
+		}
+	}
+	for _, f := range files {
+		if base := filepath.Base(f); base == "go.mod" || base == "go.sum" {
+			for _, p := range pkgs {
+				mark(p.ImportPath)
+			}
+			continue
+		}
+		if path, ok := byDir[packageDir(f)]; ok {
+			mark(path)
+		}
+	}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		for _, r := range rdeps[path] {
+			mark(r)
+		}
+	}
+	// Test imports do not propagate further: nothing imports a test.
+	var tested []string
+	for _, p := range pkgs {
+		for _, imp := range slices.Concat(p.TestImports, p.XTestImports) {
+			if affected[imp] && !affected[p.ImportPath] {
+				tested = append(tested, p.ImportPath)
+				break
+			}
+		}
+	}
+
+	result := tested
+	for path := range affected {
+		result = append(result, path)
+	}
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// packageDir returns the directory of the package a file belongs to. Files
+// under testdata belong to the package that holds the testdata directory.
+func packageDir(file string) string {
+	dir := filepath.Dir(filepath.FromSlash(file))
+	parts := strings.Split(dir, string(filepath.Separator))
+	if i := slices.Index(parts, "testdata"); i >= 0 {
+		if i == 0 {
+			return "."
+		}
+		return filepath.Join(parts[:i]...)
+	}
+	return dir
+}
+
+// AffectedPackages lists the packages of the module at dir and returns those
+// affected by a change to files.
+func AffectedPackages(ctx context.Context, dir string, files []string) ([]string, error) {
+	pkgs, err := ListPackages(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	// Compare directories with symlinks resolved, as either side may be.
+	for i := range pkgs {
+		pkgs[i].Dir = resolve(pkgs[i].Dir)
+	}
+	return Affected(pkgs, resolve(dir), files), nil
+}
+
+// Module is a Go module of a worktree and the packages of it that a change
+// affects.
+type Module struct {
+	Dir      string
+	Packages []string
+}
+
+// AffectedModules returns the modules holding files, relative to dir, with
+// the packages a change to the files affects, skipping modules with none.
+// Each file belongs to the module of the go.mod nearest above it, so modules
+// in subdirectories are found; a go.work at dir takes in every file. Files
+// outside any module are ignored.
+func AffectedModules(ctx context.Context, dir string, files []string) ([]Module, error) {
+	work := exists(filepath.Join(dir, "go.work"))
+	byModule := make(map[string][]string)
+	for _, f := range files {
+		mod, ok := ".", work
+		if !work {
+			mod, ok = moduleDir(dir, f)
+		}
+		if !ok {
+			continue
+		}
+		rel, _ := filepath.Rel(mod, filepath.FromSlash(f))
+		byModule[mod] = append(byModule[mod], filepath.ToSlash(rel))
+	}
+	var mods []Module
+	for _, mod := range slices.Sorted(maps.Keys(byModule)) {
+		modDir := filepath.Join(dir, mod)
+		pkgs, err := AffectedPackages(ctx, modDir, byModule[mod])
+		if err != nil {
+			return nil, err
+		}
+		if len(pkgs) > 0 {
+			mods = append(mods, Module{Dir: modDir, Packages: pkgs})
+		}
+	}
+	return mods, nil
+}
+
+// moduleDir returns the directory, relative to root, of the go.mod nearest
+// above file. ok is false when no directory up to root has one.
+func moduleDir(root, file string) (dir string, ok bool) {
+	dir = filepath.Dir(filepath.FromSlash(file))
+	for {
+		if exists(filepath.Join(root, dir, "go.mod")) {
+			return dir, true
+		}
+		if dir == "." || dir == string(filepath.Separator) {
+			return "", false
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func resolve(path string) string {
+	if r, err := filepath.EvalSymlinks(path); err == nil {
+		return r
+	}
+	return path
+}
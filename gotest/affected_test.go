@@ -0,0 +1,106 @@
+package gotest_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kosuke9809/gh-review/gotest"
+)
+
+func TestAffected(t *testing.T) {
+	pkgs := []gotest.Package{
+		{ImportPath: "m/a", Dir: "/r/a"},
+		{ImportPath: "m/b", Dir: "/r/b", Imports: []string{"m/a", "fmt"}},
+		{ImportPath: "m/c", Dir: "/r/c", Imports: []string{"m/b"}},
+		{ImportPath: "m/d", Dir: "/r/d", XTestImports: []string{"m/a"}},
+		{ImportPath: "m/e", Dir: "/r/e", TestImports: []string{"m/d"}},
+		{ImportPath: "m", Dir: "/r"},
+	}
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{"逆依存を辿る", []string{"a/a.go"}, []string{"m/a", "m/b", "m/c", "m/d"}},
+		{"依存されていないパッケージ", []string{"c/c.go"}, []string{"m/c"}},
+		{"テストの依存は伝播しない", []string{"d/d.go"}, []string{"m/d", "m/e"}},
+		{"testdataは親パッケージ", []string{"b/testdata/golden.txt"}, []string{"m/b", "m/c"}},
+		{"ルートのパッケージ", []string{"main.go"}, []string{"m"}},
+		{"パッケージ外のファイル", []string{"docs/README.md"}, nil},
+		{"go.modは全パッケージ", []string{"go.mod"}, []string{"m", "m/a", "m/b", "m/c", "m/d", "m/e"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gotest.Affected(pkgs, "/r", tt.files); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Affected(%q) = %q, want %q", tt.files, got, tt.want)
+			}
+		})
+	}
+}
+
+// writeModule writes files, keyed by path relative to the module root, into
+// a new module named "example.com/m".
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/m\n\ngo 1.22\n"
+	for name, body := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestAffectedPackages(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a.go": "package a\n\nfunc A() int { return 1 }\n",
+		"b/b.go": "package b\n\nimport \"example.com/m/a\"\n\nfunc B() int { return a.A() }\n",
+		"c/c.go": "package c\n",
+	})
+	got, err := gotest.AffectedPackages(context.Background(), dir, []string{"a/a.go"})
+	if err != nil {
+		t.Fatalf("AffectedPackages() error = %v", err)
+	}
+	if want := []string{"example.com/m/a", "example.com/m/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AffectedPackages() = %q, want %q", got, want)
+	}
+}
+
+func TestAffectedModules(t *testing.T) {
+	dir := t.TempDir()
+	for name, body := range map[string]string{
+		"README.md":        "readme\n",
+		"svc/go.mod":       "module example.com/svc\n\ngo 1.22\n",
+		"svc/a/a.go":       "package a\n",
+		"svc/b/b.go":       "package b\n\nimport _ \"example.com/svc/a\"\n",
+		"tools/go.mod":     "module example.com/tools\n\ngo 1.22\n",
+		"tools/gen/gen.go": "package gen\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := gotest.AffectedModules(context.Background(), dir, []string{"README.md", "svc/a/a.go", "tools/gen/gen.go"})
+	if err != nil {
+		t.Fatalf("AffectedModules() error = %v", err)
+	}
+	want := []gotest.Module{
+		{Dir: filepath.Join(dir, "svc"), Packages: []string{"example.com/svc/a", "example.com/svc/b"}},
+		{Dir: filepath.Join(dir, "tools"), Packages: []string{"example.com/tools/gen"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AffectedModules() = %+v, want %+v", got, want)
+	}
+}
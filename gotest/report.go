@@ -0,0 +1,173 @@
+package gotest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/kosuke9809/gh-review/runner"
+)
+
+// Status is the outcome of a package's tests.
+type Status string
+
+const (
+	StatusPending Status = ""
+	StatusRunning Status = "run"
+	StatusPass    Status = "pass"
+	StatusFail    Status = "fail"
+	StatusSkip    Status = "skip" // no test files
+)
+
+// Result is the outcome of a package's tests.
+type Result struct {
+	Package string
+	Status  Status
+	Elapsed time.Duration
+	Failed  []string // names of the failed tests
+	Output  []string // output of the failed tests, or of the build when it failed
+}
+
+// event is a line of `go test -json` output.
+type event struct {
+	Action      string
+	Package     string
+	Test        string
+	Elapsed     float64
+	Output      string
+	ImportPath  string // build-output events
+	FailedBuild string
+}
+
+// Report collects the results of a `go test -json` run.
+type Report struct {
+	Results []Result
+	Errors  []string // output that is not part of any package, e.g. go command errors
+
+	index  map[string]int
+	output map[string][]string // keyed by package and test; build output by package
+}
+
+// NewReport returns a report with pkgs pending.
+func NewReport(pkgs []string) *Report {
+	r := &Report{index: make(map[string]int), output: make(map[string][]string)}
+	for _, p := range pkgs {
+		r.result(p)
+	}
+	return r
+}
+
+func (r *Report) result(pkg string) *Result {
+	i, ok := r.index[pkg]
+	if !ok {
+		i = len(r.Results)
+		r.index[pkg] = i
+		r.Results = append(r.Results, Result{Package: pkg})
+	}
+	return &r.Results[i]
+}
+
+func outputKey(pkg, test string) string {
+	return pkg + "\x00" + test
+}
+
+// Add records a line of `go test -json` output.
+func (r *Report) Add(line string) {
+	var ev event
+	if err := json.Unmarshal([]byte(line), &ev); err != nil || ev.Action == "" {
+		if line = strings.TrimSpace(line); line != "" {
+			r.Errors = append(r.Errors, line)
+		}
+		return
+	}
+	if ev.Action == "build-output" {
+		// ImportPath is "pkg" or "pkg [pkg.test]".
+		pkg, _, _ := strings.Cut(ev.ImportPath, " ")
+		r.output[outputKey(pkg, "")] = append(r.output[outputKey(pkg, "")], strings.TrimRight(ev.Output, "\n"))
+		return
+	}
+	if ev.Action == "build-fail" || ev.Package == "" {
+		return
+	}
+	res := r.result(ev.Package)
+	key := outputKey(ev.Package, ev.Test)
+	switch ev.Action {
+	case "start", "run":
+		if res.Status == StatusPending {
+			res.Status = StatusRunning
+		}
+	case "output":
+		r.output[key] = append(r.output[key], strings.TrimRight(ev.Output, "\n"))
+	case "fail":
+		if ev.Test != "" {
+			res.Failed = append(res.Failed, ev.Test)
+			res.Output = append(res.Output, r.output[key]...)
+			break
+		}
+		res.Status = StatusFail
+		res.Elapsed = seconds(ev.Elapsed)
+		if ev.FailedBuild != "" {
+			pkg, _, _ := strings.Cut(ev.FailedBuild, " ")
+			res.Output = append(res.Output, r.output[outputKey(pkg, "")]...)
+		} else if len(res.Failed) == 0 {
+			// A panic outside a test or a failing TestMain.
+			res.Output = append(res.Output, r.output[key]...)
+		}
+	case "pass":
+		if ev.Test == "" {
+			res.Status = StatusPass
+			res.Elapsed = seconds(ev.Elapsed)
+		}
+	case "skip":
+		if ev.Test == "" {
+			res.Status = StatusSkip
+		}
+	}
+	if ev.Action == "pass" || ev.Action == "fail" || ev.Action == "skip" {
+		delete(r.output, key)
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond)
+}
+
+// Count returns the number of packages with status s.
+func (r *Report) Count(s Status) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == s {
+			n++
+		}
+	}
+	return n
+}
+
+// Run runs `go test -json` on pkgs in dir, passing each line of output to
+// out, typically a Report's Add. It returns the exit code of go test.
+func Run(ctx context.Context, dir string, pkgs []string, out func(line string)) (int, error) {
+	args := make([]string, len(pkgs))
+	for i, p := range pkgs {
+		// Import paths never contain quotes.
+		args[i] = "'" + p + "'"
+	}
+	c := runner.Command{Dir: dir, Script: "go test -json " + strings.Join(args, " ")}
+	return runner.Run(ctx, c, out)
+}
+
+// RunModules runs the tests of each module's packages in turn, returning the
+// first non-zero exit status. It stops at the first error.
+func RunModules(ctx context.Context, mods []Module, out func(line string)) (int, error) {
+	status := 0
+	for _, mod := range mods {
+		code, err := Run(ctx, mod.Dir, mod.Packages, out)
+		if err != nil {
+			return code, err
+		}
+		if status == 0 {
+			status = code
+		}
+	}
+	return status, nil
+}
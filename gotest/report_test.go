@@ -0,0 +1,71 @@
+package gotest_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/gotest"
+)
+
+func TestReport_Add(t *testing.T) {
+	r := gotest.NewReport([]string{"m/a", "m/b", "m/c", "m/d"})
+	for _, line := range []string{
+		`{"Action":"start","Package":"m/a"}`,
+		`{"Action":"skip","Package":"m/a","Elapsed":0}`,
+		`{"Action":"run","Package":"m/b","Test":"TestB"}`,
+		`{"Action":"output","Package":"m/b","Test":"TestB","Output":"=== RUN   TestB\n"}`,
+		`{"Action":"output","Package":"m/b","Test":"TestB","Output":"    b_test.go:3: boom\n"}`,
+		`{"Action":"fail","Package":"m/b","Test":"TestB","Elapsed":0}`,
+		`{"Action":"run","Package":"m/b","Test":"TestOK"}`,
+		`{"Action":"output","Package":"m/b","Test":"TestOK","Output":"=== RUN   TestOK\n"}`,
+		`{"Action":"pass","Package":"m/b","Test":"TestOK","Elapsed":0}`,
+		`{"Action":"fail","Package":"m/b","Elapsed":0.25}`,
+		`{"ImportPath":"m/c [m/c.test]","Action":"build-output","Output":"c/c_test.go:3:28: undefined: x\n"}`,
+		`{"ImportPath":"m/c [m/c.test]","Action":"build-fail"}`,
+		`{"Action":"fail","Package":"m/c","Elapsed":0,"FailedBuild":"m/c [m/c.test]"}`,
+		`{"Action":"run","Package":"m/d","Test":"TestD"}`,
+		`go: downloading example.com/x v1.0.0`,
+	} {
+		r.Add(line)
+	}
+	want := []gotest.Result{
+		{Package: "m/a", Status: gotest.StatusSkip},
+		{Package: "m/b", Status: gotest.StatusFail, Elapsed: 250 * time.Millisecond, Failed: []string{"TestB"}, Output: []string{"=== RUN   TestB", "    b_test.go:3: boom"}},
+		{Package: "m/c", Status: gotest.StatusFail, Output: []string{"c/c_test.go:3:28: undefined: x"}},
+		{Package: "m/d", Status: gotest.StatusRunning},
+	}
+	if !reflect.DeepEqual(r.Results, want) {
+		t.Errorf("Results = %+v\nwant %+v", r.Results, want)
+	}
+	if len(r.Errors) != 1 || !strings.HasPrefix(r.Errors[0], "go: downloading") {
+		t.Errorf("Errors = %q", r.Errors)
+	}
+	if n := r.Count(gotest.StatusFail); n != 2 {
+		t.Errorf("Count(fail) = %d, want 2", n)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a_test.go": "package a\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) {}\n",
+		"b/b_test.go": "package b\n\nimport \"testing\"\n\nfunc TestB(t *testing.T) { t.Fatal(\"boom\") }\n",
+	})
+	pkgs := []string{"example.com/m/a", "example.com/m/b"}
+	r := gotest.NewReport(pkgs)
+	code, err := gotest.Run(context.Background(), dir, pkgs, r.Add)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if code == 0 {
+		t.Error("Run() code = 0, want failure")
+	}
+	if r.Results[0].Status != gotest.StatusPass || r.Results[1].Status != gotest.StatusFail {
+		t.Errorf("Results = %+v, want a passed and b failed", r.Results)
+	}
+	if !reflect.DeepEqual(r.Results[1].Failed, []string{"TestB"}) {
+		t.Errorf("Failed = %q, want TestB", r.Results[1].Failed)
+	}
+}
@@ -0,0 +1,122 @@
+// Package highlight syntax-highlights the lines of unified diff patches.
+package highlight
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// None is the style name that turns highlighting off.
+const None = "none"
+
+// Span is a run of text in a single style.
+type Span struct {
+	Text   string
+	Color  string // "#rrggbb"; empty keeps the terminal's color
+	Bold   bool
+	Italic bool
+}
+
+// Highlighter colors code with a chroma style.
+type Highlighter struct {
+	style *chroma.Style
+}
+
+// New returns a Highlighter using the named chroma style, e.g. "monokai".
+func New(name string) (*Highlighter, error) {
+	s, ok := styles.Registry[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown style %q", name)
+	}
+	return &Highlighter{style: s}, nil
+}
+
+// Patch highlights a patch of filename in the language detected from its
+// name. It returns the spans of each patch line's content, without its
+// leading +, - or space; hunk headers get nil. ok is false when the
+// language is unknown.
+//
+// The old and new sides of each hunk are highlighted separately, so lines
+// inside multi-line strings and comments are colored as in the file.
+func (h *Highlighter) Patch(filename, patch string) (lines [][]Span, ok bool) {
+	lexer := lexers.Match(filepath.Base(filename))
+	if lexer == nil {
+		return nil, false
+	}
+	lexer = chroma.Coalesce(lexer)
+	patchLines := strings.Split(patch, "\n")
+	lines = make([][]Span, len(patchLines))
+	// idx maps each source line of a side to its patch line, or -1 for
+	// context lines that take their spans from the new side.
+	var oldSrc, newSrc []string
+	var oldIdx, newIdx []int
+	flush := func() {
+		h.fill(lexer, oldSrc, oldIdx, lines)
+		h.fill(lexer, newSrc, newIdx, lines)
+		oldSrc, newSrc, oldIdx, newIdx = nil, nil, nil, nil
+	}
+	for i, line := range patchLines {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			flush()
+		case strings.HasPrefix(line, "+"):
+			newSrc, newIdx = append(newSrc, line[1:]), append(newIdx, i)
+		case strings.HasPrefix(line, "-"):
+			oldSrc, oldIdx = append(oldSrc, line[1:]), append(oldIdx, i)
+		case strings.HasPrefix(line, `\`):
+		default:
+			code := strings.TrimPrefix(line, " ")
+			oldSrc, oldIdx = append(oldSrc, code), append(oldIdx, -1)
+			newSrc, newIdx = append(newSrc, code), append(newIdx, i)
+		}
+	}
+	flush()
+	return lines, true
+}
+
+// fill tokenises src and stores the spans of each of its lines at the patch
+// line given by idx.
+func (h *Highlighter) fill(lexer chroma.Lexer, src []string, idx []int, lines [][]Span) {
+	if len(src) == 0 {
+		return
+	}
+	it, err := lexer.Tokenise(nil, strings.Join(src, "\n")+"\n")
+	if err != nil {
+		return
+	}
+	for n, tokens := range chroma.SplitTokensIntoLines(it.Tokens()) {
+		if n >= len(idx) {
+			break
+		}
+		if idx[n] >= 0 {
+			lines[idx[n]] = h.spans(tokens)
+		}
+	}
+}
+
+// spans styles tokens, merging neighbours of the same style.
+func (h *Highlighter) spans(tokens []chroma.Token) []Span {
+	var out []Span
+	for _, t := range tokens {
+		text := strings.TrimSuffix(t.Value, "\n")
+		if text == "" {
+			continue
+		}
+		e := h.style.Get(t.Type)
+		s := Span{Text: text, Bold: e.Bold == chroma.Yes, Italic: e.Italic == chroma.Yes}
+		if e.Colour.IsSet() {
+			s.Color = e.Colour.String()
+		}
+		if n := len(out); n > 0 && out[n-1].Color == s.Color && out[n-1].Bold == s.Bold && out[n-1].Italic == s.Italic {
+			out[n-1].Text += s.Text
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package highlight_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kosuke9809/gh-review/highlight"
+)
+
+func text(spans []highlight.Span) string {
+	var b strings.Builder
+	for _, s := range spans {
+		b.WriteString(s.Text)
+	}
+	return b.String()
+}
+
+func TestNew(t *testing.T) {
+	if _, err := highlight.New("Monokai"); err != nil {
+		t.Errorf("New(Monokai) error = %v", err)
+	}
+	if _, err := highlight.New("nope"); err == nil {
+		t.Error("New(nope) error = nil, want an unknown style")
+	}
+}
+
+func TestPatch(t *testing.T) {
+	h, err := highlight.New("monokai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch := "@@ -1,3 +1,4 @@\n package main\n-var s = 1\n+var s = `a\n+func`\n\\ No newline at end of file"
+	lines, ok := h.Patch("cmd/main.go", patch)
+	if !ok {
+		t.Fatal("Patch() ok = false, want Go to be detected")
+	}
+	want := []string{"", "package main", "var s = 1", "var s = `a", "func`", ""}
+	for i, w := range want {
+		if got := text(lines[i]); got != w {
+			t.Errorf("line %d = %q, want %q", i, got, w)
+		}
+	}
+	if lines[1][0].Color == "" {
+		t.Errorf("keyword %q has no color", lines[1][0].Text)
+	}
+	// The raw string continues from the previous added line.
+	if s := lines[4][0]; s.Text != "func`" || s.Color != lines[3][len(lines[3])-1].Color {
+		t.Errorf("raw string line = %+v, want it colored as the string it continues", lines[4])
+	}
+}
+
+func TestPatch_UnknownLanguage(t *testing.T) {
+	h, err := highlight.New("monokai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := h.Patch("data.unknownext", "@@ -1 +1 @@\n+x"); ok {
+		t.Error("Patch() ok = true, want false for an unknown language")
+	}
+}
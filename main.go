@@ -6,8 +6,10 @@ import (
 	"os/exec"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kosuke9809/gh-review/config"
 	"github.com/kosuke9809/gh-review/git"
 	"github.com/kosuke9809/gh-review/github"
+	"github.com/kosuke9809/gh-review/state"
 	"github.com/kosuke9809/gh-review/tui"
 	"golang.org/x/term"
 )
@@ -56,12 +58,23 @@ func run() error {
 		return fmt.Errorf("failed to get current GitHub user: %w", err)
 	}
 
+	cfg, err := config.Load(config.DefaultPath())
+	if err != nil {
+		return err
+	}
+
+	store := state.NewStore(state.DefaultPath())
+	st, err := store.Load()
+	if err != nil {
+		return err
+	}
+
 	width, height, err := term.GetSize(int(os.Stdout.Fd()))
 	if err != nil {
 		width, height = 120, 40
 	}
 
-	m := tui.New(owner, repo, repoRoot, currentUser, client, width, height)
+	m := tui.New(owner, repo, repoRoot, currentUser, client, cfg, store, st, width, height)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("TUI error: %w", err)
@@ -1,6 +1,11 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
 
 type PRFilter int
 
@@ -8,8 +13,24 @@ const (
 	FilterReviewRequested PRFilter = iota // review-requested:@me
 	FilterAuthored                        // author:@me
 	FilterAll                             // all open PRs
+
+	builtinFilterCount = 3
 )
 
+// CustomFilterAt returns the PRFilter selecting the i-th user-defined filter.
+func CustomFilterAt(i int) PRFilter {
+	return builtinFilterCount + PRFilter(i)
+}
+
+// CustomIndex reports the index of a user-defined filter, or false for a
+// built-in one.
+func (f PRFilter) CustomIndex() (int, bool) {
+	if f < builtinFilterCount {
+		return 0, false
+	}
+	return int(f - builtinFilterCount), true
+}
+
 func (f PRFilter) Label() string {
 	switch f {
 	case FilterReviewRequested:
@@ -22,8 +43,85 @@ func (f PRFilter) Label() string {
 	return ""
 }
 
-func (f PRFilter) Next() PRFilter {
-	return (f + 1) % 3
+// Next returns the filter after f, cycling through the built-in filters
+// followed by customCount user-defined ones.
+func (f PRFilter) Next(customCount int) PRFilter {
+	return (f + 1) % PRFilter(builtinFilterCount+customCount)
+}
+
+// CustomFilter is a user-defined filter declared in the config file.
+// Empty fields match every PR.
+type CustomFilter struct {
+	Name            string
+	Authors         []string // "@me" matches currentUser
+	Base            string   // glob, e.g. "release/*"
+	Head            string   // glob
+	Labels          []string // all must be present
+	ReviewRequested bool
+	OlderThan       time.Duration
+	Sizes           []SizeClass // any of these; PRs without loaded files never match
+}
+
+// Match reports whether pr satisfies every condition of c.
+func (c CustomFilter) Match(pr PR, currentUser string, now time.Time) bool {
+	if len(c.Authors) > 0 {
+		found := false
+		for _, a := range c.Authors {
+			if a == pr.Author || (a == "@me" && pr.Author == currentUser) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if c.Base != "" {
+		if ok, _ := path.Match(c.Base, pr.BaseRef); !ok {
+			return false
+		}
+	}
+	if c.Head != "" {
+		if ok, _ := path.Match(c.Head, pr.HeadRef); !ok {
+			return false
+		}
+	}
+	for _, l := range c.Labels {
+		if !pr.HasLabel(l) {
+			return false
+		}
+	}
+	if c.ReviewRequested && !pr.IsReviewRequested {
+		return false
+	}
+	if c.OlderThan > 0 && now.Sub(pr.WaitingSince()) < c.OlderThan {
+		return false
+	}
+	if len(c.Sizes) > 0 {
+		size := pr.Size()
+		found := false
+		for _, sc := range c.Sizes {
+			if sc == size {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterCustomPRs returns PRs matching the user-defined filter c.
+func FilterCustomPRs(prs []PR, c CustomFilter, currentUser string, now time.Time) []PR {
+	var result []PR
+	for _, pr := range prs {
+		if c.Match(pr, currentUser, now) {
+			result = append(result, pr)
+		}
+	}
+	return result
 }
 
 // FilterPRs returns PRs matching the given filter for currentUser.
@@ -86,30 +184,135 @@ type Comment struct {
 
 type DiffFile struct {
 	Filename  string
+	Previous  string // name before a rename; empty otherwise
 	Patch     string
 	Additions int
 	Deletions int
 }
 
 type PR struct {
-	Number       int
-	Title        string
-	Author       string
-	BaseRef      string
-	HeadRef      string
-	HeadSHA      string
-	Body         string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	HTMLURL      string
-	CIStatus     CIStatus
-	CheckRuns    []CheckRun
-	Reviews      []Review
-	Comments     []Comment
-	DiffFiles    []DiffFile
-	ReviewState         ReviewState
-	IsReviewRequested   bool
-	HasWorktree         bool
-	WorktreePath        string
-	DetailLoaded        bool // true after lazy detail fetch completes
+	Number             int
+	Title              string
+	Author             string
+	BaseRef            string
+	BaseSHA            string
+	HeadRef            string
+	HeadSHA            string
+	HeadRepo           string // owner/name of the head repository; empty if it was deleted
+	HeadCloneURL       string
+	HeadSSHURL         string
+	HeadEditable       bool // maintainers may push to a fork's head branch
+	Body               string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	RequestedAt        time.Time // latest review_requested event for me; zero if unknown
+	HTMLURL            string
+	Labels             []string
+	RequestedReviewers []string             // logins still requested
+	RequestedTeams     []string             // team slugs still requested
+	ReviewRequests     map[string]time.Time // latest request of each reviewer, keyed by ReviewerKey; nil if unknown
+	CIStatus           CIStatus
+	CheckRuns          []CheckRun
+	Reviews            []Review
+	Comments           []Comment
+	DiffFiles          []DiffFile
+	ReviewState        ReviewState
+	IsReviewRequested  bool
+	HasWorktree        bool
+	WorktreePath       string
+	WorktreeHEAD       string          // commit checked out in the worktree; empty if unknown
+	WorktreeBranch     *TrackingStatus // nil when the worktree is detached
+	Hooks              HookStatus      // worktree hooks run this session
+	LocalRun           *LocalRun       // last command run in the worktree; nil if none
+	DetailLoaded       bool            // true after lazy detail fetch completes
+	FilesLoaded        bool            // DiffFiles are known; kept across refreshes while the head is unchanged
+	Snooze             *Snooze         // local snooze or mute; nil when not snoozed
+	Priority           PriorityScore
+	ReviewEstimate     time.Duration // estimated from my review history; 0 if unknown
+	SLA                SLALevel
+	Waiting            time.Duration // working time waiting on me
+}
+
+// WaitingSince returns when my review was requested, falling back to the
+// PR's creation time when the request time is unknown.
+func (pr PR) WaitingSince() time.Time {
+	if !pr.RequestedAt.IsZero() {
+		return pr.RequestedAt
+	}
+	return pr.CreatedAt
+}
+
+// PRKey identifies a PR across repositories in the local state file.
+func PRKey(repo string, number int) string {
+	return fmt.Sprintf("%s#%d", repo, number)
+}
+
+// PRKeyRepo returns the repository of a key made by PRKey.
+func PRKeyRepo(key string) string {
+	repo, _, _ := strings.Cut(key, "#")
+	return repo
+}
+
+// TrackingStatus is the push state of a worktree checked out on a branch.
+type TrackingStatus struct {
+	Branch   string
+	Upstream string // e.g. "origin/fix"; empty when the branch tracks nothing
+	Ahead    int    // local commits not pushed
+	Behind   int    // upstream commits not pulled, as of the last fetch
+}
+
+// HookStatus is the state of the hooks last run in a PR's worktree.
+type HookStatus int
+
+const (
+	HookNone HookStatus = iota
+	HookRunning
+	HookFailed
+	HookOK
+)
+
+// LocalRun is the outcome of the last command run in a PR's worktree from
+// the Run screen.
+type LocalRun struct {
+	Name     string    `json:"name"` // preset name, or the ad-hoc command
+	ExitCode int       `json:"exit_code"`
+	HeadSHA  string    `json:"head_sha,omitempty"` // commit checked out when it ran
+	At       time.Time `json:"at"`
+	Running  bool      `json:"-"`
+}
+
+// Passed reports whether the command exited with status 0.
+func (r LocalRun) Passed() bool {
+	return !r.Running && r.ExitCode == 0
+}
+
+// Stale reports whether the command ran against a commit other than the PR's
+// current head.
+func (r LocalRun) Stale(pr PR) bool {
+	return !r.Running && r.HeadSHA != "" && pr.HeadSHA != "" && r.HeadSHA != pr.HeadSHA
+}
+
+// WorktreeOutdated reports whether the PR's worktree is checked out at a
+// commit other than the PR's current head. A branch worktree with commits
+// waiting to be pushed is not outdated.
+func (pr PR) WorktreeOutdated() bool {
+	if pr.WorktreeBranch != nil && pr.WorktreeBranch.Ahead > 0 {
+		return false
+	}
+	return pr.HasWorktree && pr.WorktreeHEAD != "" && pr.HeadSHA != "" && pr.WorktreeHEAD != pr.HeadSHA
+}
+
+// IsFork reports whether the PR's head branch lives outside baseRepo.
+func (pr PR) IsFork(baseRepo string) bool {
+	return pr.HeadRepo != "" && !strings.EqualFold(pr.HeadRepo, baseRepo)
+}
+
+// HasLabel reports whether the PR carries the named label (case-insensitive).
+func (pr PR) HasLabel(name string) bool {
+	for _, l := range pr.Labels {
+		if strings.EqualFold(l, name) {
+			return true
+		}
+	}
+	return false
 }
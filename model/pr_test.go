@@ -2,6 +2,7 @@ package model_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kosuke9809/gh-review/model"
 )
@@ -54,3 +55,118 @@ func TestFilterPRs(t *testing.T) {
 		})
 	}
 }
+
+func TestPRFilter_Next(t *testing.T) {
+	f := model.FilterAll
+	if got := f.Next(0); got != model.FilterReviewRequested {
+		t.Errorf("FilterAll.Next(0) = %v, want FilterReviewRequested", got)
+	}
+	if got := f.Next(2); got != model.CustomFilterAt(0) {
+		t.Errorf("FilterAll.Next(2) = %v, want CustomFilterAt(0)", got)
+	}
+	if got := model.CustomFilterAt(1).Next(2); got != model.FilterReviewRequested {
+		t.Errorf("CustomFilterAt(1).Next(2) = %v, want FilterReviewRequested", got)
+	}
+	if i, ok := model.CustomFilterAt(1).CustomIndex(); !ok || i != 1 {
+		t.Errorf("CustomFilterAt(1).CustomIndex() = %d, %v", i, ok)
+	}
+	if _, ok := model.FilterAuthored.CustomIndex(); ok {
+		t.Error("FilterAuthored.CustomIndex() ok = true, want false")
+	}
+}
+
+func TestFilterCustomPRs(t *testing.T) {
+	now := time.Now()
+	prs := []model.PR{
+		{Number: 1, Author: "alice", BaseRef: "release/1.0", CreatedAt: now.Add(-72 * time.Hour), IsReviewRequested: true},
+		{Number: 2, Author: "dependabot[bot]", BaseRef: "main", Labels: []string{"Dependencies"}, CreatedAt: now},
+		{Number: 3, Author: "me", BaseRef: "release/2.0", CreatedAt: now.Add(-time.Hour), IsReviewRequested: true, FilesLoaded: true},
+	}
+
+	tests := []struct {
+		name     string
+		filter   model.CustomFilter
+		wantNums []int
+	}{
+		{"base glob と @me", model.CustomFilter{Authors: []string{"@me", "alice"}, Base: "release/*"}, []int{1, 3}},
+		{"ラベル（大文字小文字を無視）", model.CustomFilter{Labels: []string{"dependencies"}}, []int{2}},
+		{"2日以上待っているレビュー依頼", model.CustomFilter{ReviewRequested: true, OlderThan: 48 * time.Hour}, []int{1}},
+		{"条件なしは全件", model.CustomFilter{}, []int{1, 2, 3}},
+		{"サイズ（未取得は対象外）", model.CustomFilter{Sizes: []model.SizeClass{model.SizeXS}}, []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := model.FilterCustomPRs(prs, tt.filter, "me", now)
+			if len(got) != len(tt.wantNums) {
+				t.Fatalf("FilterCustomPRs() len = %d, want %d", len(got), len(tt.wantNums))
+			}
+			for i, pr := range got {
+				if pr.Number != tt.wantNums[i] {
+					t.Errorf("FilterCustomPRs()[%d].Number = %d, want %d", i, pr.Number, tt.wantNums[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPR_WorktreeOutdated(t *testing.T) {
+	tests := []struct {
+		name string
+		pr   model.PR
+		want bool
+	}{
+		{"worktreeなし", model.PR{HeadSHA: "b"}, false},
+		{"HEADが一致", model.PR{HasWorktree: true, WorktreeHEAD: "a", HeadSHA: "a"}, false},
+		{"HEADが古い", model.PR{HasWorktree: true, WorktreeHEAD: "a", HeadSHA: "b"}, true},
+		{"HEAD不明", model.PR{HasWorktree: true, HeadSHA: "b"}, false},
+		{"未pushのコミットあり", model.PR{HasWorktree: true, WorktreeHEAD: "a", HeadSHA: "b", WorktreeBranch: &model.TrackingStatus{Ahead: 1}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pr.WorktreeOutdated(); got != tt.want {
+				t.Errorf("WorktreeOutdated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPR_IsFork(t *testing.T) {
+	tests := []struct {
+		name     string
+		headRepo string
+		want     bool
+	}{
+		{"同じリポジトリ", "octo/app", false},
+		{"大文字小文字の違い", "Octo/App", false},
+		{"フォーク", "alice/app", true},
+		{"削除されたフォーク", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (model.PR{HeadRepo: tt.headRepo}).IsFork("octo/app"); got != tt.want {
+				t.Errorf("IsFork() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalRun_Stale(t *testing.T) {
+	tests := []struct {
+		name string
+		run  model.LocalRun
+		want bool
+	}{
+		{"同じコミット", model.LocalRun{HeadSHA: "a"}, false},
+		{"古いコミット", model.LocalRun{HeadSHA: "b"}, true},
+		{"コミット不明", model.LocalRun{}, false},
+		{"実行中", model.LocalRun{HeadSHA: "b", Running: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.run.Stale(model.PR{HeadSHA: "a"}); got != tt.want {
+				t.Errorf("Stale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
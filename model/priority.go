@@ -0,0 +1,97 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// PriorityWeights tunes how each signal contributes to a PR's priority score.
+type PriorityWeights struct {
+	WaitPerDay float64            // per day since my review was requested
+	Blocking   float64            // mine is the only approval still missing
+	CIPass     float64            // CI passed
+	CIFail     float64            // CI failed (usually negative)
+	SmallDiff  float64            // full bonus for a tiny diff, fading to 0
+	DiffScale  int                // changed lines at which SmallDiff reaches 0
+	ReReview   float64            // the author pushed after my review (UPD)
+	Labels     map[string]float64 // per label name, case-insensitive
+
+	RequiredApprovals int // approvals a PR needs before it can merge
+}
+
+// DefaultPriorityWeights returns the weights used when the config is silent.
+func DefaultPriorityWeights() PriorityWeights {
+	return PriorityWeights{
+		WaitPerDay: 2,
+		Blocking:   10,
+		CIPass:     3,
+		CIFail:     -5,
+		SmallDiff:  5,
+		DiffScale:  500,
+		ReReview:   8,
+		Labels:     map[string]float64{"urgent": 15},
+
+		RequiredApprovals: 1,
+	}
+}
+
+// ScoreComponent is one explained term of a PriorityScore.
+type ScoreComponent struct {
+	Name   string
+	Detail string
+	Points float64
+}
+
+// PriorityScore is the total priority of a PR and how it was reached.
+type PriorityScore struct {
+	Total      float64
+	Components []ScoreComponent
+}
+
+// Priority computes the review priority of pr for the current user.
+func Priority(pr PR, w PriorityWeights, now time.Time) PriorityScore {
+	var s PriorityScore
+	add := func(name, detail string, points float64) {
+		if points == 0 {
+			return
+		}
+		s.Components = append(s.Components, ScoreComponent{Name: name, Detail: detail, Points: points})
+		s.Total += points
+	}
+
+	if pr.IsReviewRequested {
+		days := now.Sub(pr.WaitingSince()).Hours() / 24
+		add("Waiting", fmt.Sprintf("%.1fd since requested", days), days*w.WaitPerDay)
+		if pr.DetailLoaded && w.RequiredApprovals > 0 && pr.Approvals() == w.RequiredApprovals-1 && len(pr.standingChangeRequests()) == 0 {
+			add("Blocking", "only missing approval", w.Blocking)
+		}
+	}
+	switch pr.CIStatus {
+	case CIStatusPass:
+		add("CI", "passing", w.CIPass)
+	case CIStatusFail:
+		add("CI", "failing", w.CIFail)
+	}
+	if pr.FilesLoaded && w.DiffScale > 0 {
+		lines := pr.ChangedLines()
+		ratio := 1 - float64(lines)/float64(w.DiffScale)
+		if ratio > 0 {
+			add("Size", fmt.Sprintf("%d lines changed", lines), ratio*w.SmallDiff)
+		}
+	}
+	if pr.ReviewState == ReviewStateUpd {
+		add("Re-review", "updated since my review", w.ReReview)
+	}
+	names := make([]string, 0, len(w.Labels))
+	for name := range w.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if pr.HasLabel(name) {
+			add("Label", name, w.Labels[name])
+		}
+	}
+	return s
+}
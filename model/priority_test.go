@@ -0,0 +1,110 @@
+package model_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestPriority(t *testing.T) {
+	now := time.Now()
+	w := model.PriorityWeights{
+		WaitPerDay: 1,
+		Blocking:   10,
+		CIPass:     3,
+		CIFail:     -5,
+		SmallDiff:  4,
+		DiffScale:  100,
+		ReReview:   8,
+		Labels:     map[string]float64{"urgent": 20},
+
+		RequiredApprovals: 2,
+	}
+	approved := []model.Review{{Author: "alice", State: "APPROVED", CreatedAt: now}}
+
+	tests := []struct {
+		name      string
+		pr        model.PR
+		wantTotal float64
+		wantParts []string
+	}{
+		{
+			name: "依頼から2日、残る承認は自分だけ、CI成功",
+			pr: model.PR{
+				IsReviewRequested:  true,
+				CreatedAt:          now.Add(-48 * time.Hour),
+				RequestedReviewers: []string{"me", "bob"},
+				Reviews:            approved,
+				DetailLoaded:       true,
+				CIStatus:           model.CIStatusPass,
+			},
+			wantTotal: 2 + 10 + 3,
+			wantParts: []string{"Waiting", "Blocking", "CI"},
+		},
+		{
+			name: "他のレビュアーが変更を要求中",
+			pr: model.PR{
+				IsReviewRequested: true,
+				CreatedAt:         now,
+				Reviews:           append([]model.Review{{Author: "bob", State: "CHANGES_REQUESTED", CreatedAt: now}}, approved...),
+				DetailLoaded:      true,
+			},
+			wantTotal: 0,
+			wantParts: nil,
+		},
+		{
+			name: "承認が二つ足りない",
+			pr: model.PR{
+				IsReviewRequested:  true,
+				CreatedAt:          now,
+				RequestedReviewers: []string{"me", "alice"},
+				Reviews:            approved,
+				DetailLoaded:       true,
+			},
+			wantTotal: 0,
+			wantParts: nil,
+		},
+		{
+			name:      "レビュー未取得",
+			pr:        model.PR{IsReviewRequested: true, CreatedAt: now, RequestedReviewers: []string{"me"}},
+			wantTotal: 0,
+			wantParts: nil,
+		},
+		{
+			name: "再レビュー待ち、小さな差分、urgent ラベル",
+			pr: model.PR{
+				ReviewState: model.ReviewStateUpd,
+				FilesLoaded: true,
+				DiffFiles:   []model.DiffFile{{Additions: 20, Deletions: 5}},
+				Labels:      []string{"Urgent"},
+			},
+			wantTotal: 3 + 8 + 20,
+			wantParts: []string{"Size", "Re-review", "Label"},
+		},
+		{
+			name:      "CI失敗は減点",
+			pr:        model.PR{CIStatus: model.CIStatusFail},
+			wantTotal: -5,
+			wantParts: []string{"CI"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := model.Priority(tt.pr, w, now)
+			if math.Abs(got.Total-tt.wantTotal) > 1e-9 {
+				t.Errorf("Total = %v, want %v", got.Total, tt.wantTotal)
+			}
+			if len(got.Components) != len(tt.wantParts) {
+				t.Fatalf("Components = %+v, want %v", got.Components, tt.wantParts)
+			}
+			for i, c := range got.Components {
+				if c.Name != tt.wantParts[i] {
+					t.Errorf("Components[%d].Name = %q, want %q", i, c.Name, tt.wantParts[i])
+				}
+			}
+		})
+	}
+}
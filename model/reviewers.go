@@ -0,0 +1,178 @@
+package model
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReviewerLoad aggregates the outstanding review work of one user or team
+// across open PRs.
+type ReviewerLoad struct {
+	Name             string // login, or team slug when IsTeam
+	IsTeam           bool
+	Requested        int       // open PRs still requesting their review
+	OldestSince      time.Time // when the oldest of those reviews was requested
+	ChangesRequested int       // open PRs where their latest review requests changes
+}
+
+// Label returns "@login" for users and "team:slug" for teams.
+func (r ReviewerLoad) Label() string {
+	if r.IsTeam {
+		return "team:" + r.Name
+	}
+	return "@" + r.Name
+}
+
+// ReviewerKey identifies a user, or a team when team is set, in
+// PR.ReviewRequests.
+func ReviewerKey(name string, team bool) string {
+	if team {
+		return "team:" + name
+	}
+	return name
+}
+
+// ReviewerLoads aggregates reviewer load over prs, busiest first. Only PRs
+// whose Reviews are loaded contribute to ChangesRequested. Requests are
+// dated by PR.ReviewRequests, falling back to the PR's creation time.
+func ReviewerLoads(prs []PR) []ReviewerLoad {
+	loads := make(map[string]*ReviewerLoad)
+	get := func(name string, team bool) *ReviewerLoad {
+		key := ReviewerKey(name, team)
+		l, ok := loads[key]
+		if !ok {
+			l = &ReviewerLoad{Name: name, IsTeam: team}
+			loads[key] = l
+		}
+		return l
+	}
+	request := func(l *ReviewerLoad, since time.Time) {
+		l.Requested++
+		if l.OldestSince.IsZero() || since.Before(l.OldestSince) {
+			l.OldestSince = since
+		}
+	}
+
+	for _, pr := range prs {
+		for _, r := range pr.RequestedReviewers {
+			request(get(r, false), pr.requestedSince(r, false))
+		}
+		for _, t := range pr.RequestedTeams {
+			request(get(t, true), pr.requestedSince(t, true))
+		}
+		for _, r := range BlockingReviewers(pr) {
+			get(r, false).ChangesRequested++
+		}
+	}
+
+	result := make([]ReviewerLoad, 0, len(loads))
+	for _, l := range loads {
+		result = append(result, *l)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Requested != b.Requested {
+			return a.Requested > b.Requested
+		}
+		if a.ChangesRequested != b.ChangesRequested {
+			return a.ChangesRequested > b.ChangesRequested
+		}
+		return a.Label() < b.Label()
+	})
+	return result
+}
+
+// requestedSince returns when the review of name was last requested on pr,
+// or when pr was created if that is unknown.
+func (pr PR) requestedSince(name string, team bool) time.Time {
+	if t := pr.ReviewRequests[ReviewerKey(name, team)]; !t.IsZero() {
+		return t
+	}
+	return pr.CreatedAt
+}
+
+// BlockingReviewers returns the reviewers whose latest decisive review on pr
+// requests changes.
+func BlockingReviewers(pr PR) []string {
+	var result []string
+	for author, r := range latestDecisiveReviews(pr) {
+		if r.State == "CHANGES_REQUESTED" {
+			result = append(result, author)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Approvals returns how many reviewers approved pr in their latest decisive
+// review, leaving out those whose review is requested again.
+func (pr PR) Approvals() int {
+	n := 0
+	for author, r := range latestDecisiveReviews(pr) {
+		if r.State == "APPROVED" && !pr.requests(author) {
+			n++
+		}
+	}
+	return n
+}
+
+// standingChangeRequests returns the reviewers blocking pr with requested
+// changes, leaving out those whose review is requested again.
+func (pr PR) standingChangeRequests() []string {
+	var result []string
+	for _, r := range BlockingReviewers(pr) {
+		if !pr.requests(r) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+func (pr PR) requests(login string) bool {
+	for _, r := range pr.RequestedReviewers {
+		if strings.EqualFold(r, login) {
+			return true
+		}
+	}
+	return false
+}
+
+// latestDecisiveReviews returns each reviewer's latest approval, change
+// request or dismissal.
+func latestDecisiveReviews(pr PR) map[string]Review {
+	latest := make(map[string]Review)
+	for _, r := range pr.Reviews {
+		if r.State != "APPROVED" && r.State != "CHANGES_REQUESTED" && r.State != "DISMISSED" {
+			continue
+		}
+		if prev, ok := latest[r.Author]; !ok || r.CreatedAt.After(prev.CreatedAt) {
+			latest[r.Author] = r
+		}
+	}
+	return latest
+}
+
+// InvolvesReviewer reports whether pr is requesting a review from, or is
+// blocked by changes requested by, the given reviewer.
+func (pr PR) InvolvesReviewer(name string, team bool) bool {
+	if team {
+		for _, t := range pr.RequestedTeams {
+			if strings.EqualFold(t, name) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, r := range pr.RequestedReviewers {
+		if strings.EqualFold(r, name) {
+			return true
+		}
+	}
+	for _, r := range BlockingReviewers(pr) {
+		if strings.EqualFold(r, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestReviewerLoads(t *testing.T) {
+	now := time.Now()
+	prs := []model.PR{
+		{
+			Number: 1, CreatedAt: now.Add(-96 * time.Hour), RequestedAt: now.Add(-time.Hour),
+			RequestedReviewers: []string{"alice", "bob"}, RequestedTeams: []string{"core"},
+			ReviewRequests: map[string]time.Time{"alice": now.Add(-72 * time.Hour), "bob": now.Add(-time.Hour)},
+		},
+		{Number: 2, CreatedAt: now.Add(-24 * time.Hour), RequestedReviewers: []string{"alice"}},
+		{Number: 3, CreatedAt: now, Reviews: []model.Review{
+			{Author: "carol", State: "CHANGES_REQUESTED", CreatedAt: now.Add(-2 * time.Hour)},
+			{Author: "bob", State: "CHANGES_REQUESTED", CreatedAt: now.Add(-2 * time.Hour)},
+			{Author: "bob", State: "APPROVED", CreatedAt: now.Add(-time.Hour)},
+		}},
+	}
+
+	loads := model.ReviewerLoads(prs)
+	want := []struct {
+		label            string
+		requested        int
+		changesRequested int
+	}{
+		{"@alice", 2, 0},
+		{"@bob", 1, 0},
+		{"team:core", 1, 0},
+		{"@carol", 0, 1},
+	}
+	if len(loads) != len(want) {
+		t.Fatalf("ReviewerLoads() = %+v, want %d entries", loads, len(want))
+	}
+	for i, w := range want {
+		l := loads[i]
+		if l.Label() != w.label || l.Requested != w.requested || l.ChangesRequested != w.changesRequested {
+			t.Errorf("loads[%d] = %s %d/%d, want %s %d/%d", i, l.Label(), l.Requested, l.ChangesRequested, w.label, w.requested, w.changesRequested)
+		}
+	}
+	since := []time.Time{now.Add(-72 * time.Hour), now.Add(-time.Hour), prs[0].CreatedAt}
+	for i, want := range since {
+		if !loads[i].OldestSince.Equal(want) {
+			t.Errorf("%s OldestSince = %v, want %v", loads[i].Label(), loads[i].OldestSince, want)
+		}
+	}
+}
+
+func TestPR_InvolvesReviewer(t *testing.T) {
+	pr := model.PR{
+		RequestedReviewers: []string{"alice"},
+		RequestedTeams:     []string{"core"},
+		Reviews:            []model.Review{{Author: "carol", State: "CHANGES_REQUESTED"}},
+	}
+	tests := []struct {
+		name string
+		team bool
+		want bool
+	}{
+		{"alice", false, true},
+		{"carol", false, true},
+		{"core", true, true},
+		{"core", false, false},
+		{"dave", false, false},
+	}
+	for _, tt := range tests {
+		if got := pr.InvolvesReviewer(tt.name, tt.team); got != tt.want {
+			t.Errorf("InvolvesReviewer(%q, %v) = %v, want %v", tt.name, tt.team, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+type SizeClass string
+
+const (
+	SizeXS  SizeClass = "XS"  // < 10 lines
+	SizeS   SizeClass = "S"   // < 30 lines
+	SizeM   SizeClass = "M"   // < 100 lines
+	SizeL   SizeClass = "L"   // < 500 lines
+	SizeXL  SizeClass = "XL"  // < 1000 lines
+	SizeXXL SizeClass = "XXL" // 1000+ lines
+)
+
+// SizeClassFor classifies a number of changed lines.
+func SizeClassFor(lines int) SizeClass {
+	switch {
+	case lines < 10:
+		return SizeXS
+	case lines < 30:
+		return SizeS
+	case lines < 100:
+		return SizeM
+	case lines < 500:
+		return SizeL
+	case lines < 1000:
+		return SizeXL
+	}
+	return SizeXXL
+}
+
+var (
+	generatedDirs  = []string{"vendor", "node_modules", "third_party", "dist"}
+	generatedFiles = map[string]bool{
+		"go.sum": true, "package-lock.json": true, "yarn.lock": true, "pnpm-lock.yaml": true,
+		"Cargo.lock": true, "Gemfile.lock": true, "poetry.lock": true, "composer.lock": true,
+		"Pipfile.lock": true, "mix.lock": true, "flake.lock": true,
+	}
+	generatedSuffixes = []string{
+		".lock", ".pb.go", "_generated.go", ".gen.go", "_gen.go", ".min.js", ".min.css", ".snap", ".pb.ts", "_pb2.py",
+	}
+)
+
+// IsGeneratedFile reports whether a changed file is generated, vendored or
+// a lockfile, and so should not count towards the PR's review size.
+func IsGeneratedFile(name string) bool {
+	for _, dir := range strings.Split(path.Dir(name), "/") {
+		for _, g := range generatedDirs {
+			if dir == g {
+				return true
+			}
+		}
+	}
+	base := path.Base(name)
+	if generatedFiles[base] || strings.HasPrefix(base, "zz_generated") {
+		return true
+	}
+	for _, s := range generatedSuffixes {
+		if strings.HasSuffix(base, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReviewableLines returns the changed lines excluding generated files.
+func (pr PR) ReviewableLines() int {
+	n := 0
+	for _, f := range pr.DiffFiles {
+		if !IsGeneratedFile(f.Filename) {
+			n += f.Additions + f.Deletions
+		}
+	}
+	return n
+}
+
+// Size returns the PR's size class, or "" before its files are loaded.
+func (pr PR) Size() SizeClass {
+	if !pr.FilesLoaded {
+		return ""
+	}
+	return SizeClassFor(pr.ReviewableLines())
+}
+
+// ReviewRecord is one review I completed, recorded locally to estimate how
+// long future reviews take.
+type ReviewRecord struct {
+	Repo       string        `json:"repo"`
+	Number     int           `json:"number"`
+	Lines      int           `json:"lines"`
+	Duration   time.Duration `json:"duration"`
+	ReviewedAt time.Time     `json:"reviewed_at"`
+}
+
+// minEstimateSamples is how many records an estimate needs.
+const minEstimateSamples = 3
+
+// EstimateReviewTime estimates how long reviewing lines changed lines will
+// take, from the median of past reviews of the same size class, or failing
+// that the median time per line across all records.
+func EstimateReviewTime(history []ReviewRecord, lines int) (time.Duration, bool) {
+	class := SizeClassFor(lines)
+	var same []float64
+	var perLine []float64
+	for _, r := range history {
+		if r.Duration <= 0 {
+			continue
+		}
+		if SizeClassFor(r.Lines) == class {
+			same = append(same, float64(r.Duration))
+		}
+		if r.Lines > 0 {
+			perLine = append(perLine, float64(r.Duration)/float64(r.Lines))
+		}
+	}
+	if len(same) >= minEstimateSamples {
+		return time.Duration(median(same)), true
+	}
+	if len(perLine) >= minEstimateSamples {
+		return time.Duration(median(perLine) * float64(lines)), true
+	}
+	return 0, false
+}
+
+func median(xs []float64) float64 {
+	s := append([]float64(nil), xs...)
+	sort.Float64s(s)
+	mid := len(s) / 2
+	if len(s)%2 == 0 {
+		return (s[mid-1] + s[mid]) / 2
+	}
+	return s[mid]
+}
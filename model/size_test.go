@@ -0,0 +1,85 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestSizeClassFor(t *testing.T) {
+	tests := []struct {
+		lines int
+		want  model.SizeClass
+	}{
+		{0, model.SizeXS},
+		{9, model.SizeXS},
+		{10, model.SizeS},
+		{99, model.SizeM},
+		{100, model.SizeL},
+		{999, model.SizeXL},
+		{5000, model.SizeXXL},
+	}
+	for _, tt := range tests {
+		if got := model.SizeClassFor(tt.lines); got != tt.want {
+			t.Errorf("SizeClassFor(%d) = %v, want %v", tt.lines, got, tt.want)
+		}
+	}
+}
+
+func TestIsGeneratedFile(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"go.sum", true},
+		{"web/package-lock.json", true},
+		{"vendor/github.com/x/y.go", true},
+		{"api/v1/service.pb.go", true},
+		{"pkg/apis/zz_generated.deepcopy.go", true},
+		{"static/app.min.js", true},
+		{"main.go", false},
+		{"docs/vendoring.md", false},
+	}
+	for _, tt := range tests {
+		if got := model.IsGeneratedFile(tt.name); got != tt.want {
+			t.Errorf("IsGeneratedFile(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPR_Size_ExcludesGenerated(t *testing.T) {
+	pr := model.PR{
+		FilesLoaded: true,
+		DiffFiles: []model.DiffFile{
+			{Filename: "main.go", Additions: 20, Deletions: 5},
+			{Filename: "go.sum", Additions: 400},
+		},
+	}
+	if got := pr.Size(); got != model.SizeS {
+		t.Errorf("Size() = %v, want S", got)
+	}
+	if got := (model.PR{}).Size(); got != "" {
+		t.Errorf("Size() before detail load = %q, want empty", got)
+	}
+}
+
+func TestEstimateReviewTime(t *testing.T) {
+	history := []model.ReviewRecord{
+		{Lines: 50, Duration: 10 * time.Minute},
+		{Lines: 60, Duration: 20 * time.Minute},
+		{Lines: 80, Duration: 30 * time.Minute},
+		{Lines: 400, Duration: 40 * time.Minute},
+	}
+	if got, ok := model.EstimateReviewTime(history, 70); !ok || got != 20*time.Minute {
+		t.Errorf("EstimateReviewTime(M) = %v, %v; want 20m from same class", got, ok)
+	}
+	// L has a single sample, so the median time per line is used:
+	// 6s, 12s, 20s, 22.5s → 16s.
+	if got, ok := model.EstimateReviewTime(history, 200); !ok || got != 200*16*time.Second {
+		t.Errorf("EstimateReviewTime(L) = %v, %v; want %v", got, ok, 200*16*time.Second)
+	}
+	if _, ok := model.EstimateReviewTime(history[:2], 70); ok {
+		t.Error("EstimateReviewTime with 2 samples ok = true, want false")
+	}
+}
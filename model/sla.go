@@ -0,0 +1,123 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// WorkSchedule describes when reviews are expected to happen.
+type WorkSchedule struct {
+	Start    time.Duration // offset from midnight, e.g. 9h
+	End      time.Duration // offset from midnight, e.g. 18h
+	Days     [7]bool       // indexed by time.Weekday
+	Location *time.Location
+}
+
+// DefaultWorkSchedule is 09:00–18:00 local time, Monday to Friday.
+func DefaultWorkSchedule() WorkSchedule {
+	w := WorkSchedule{Start: 9 * time.Hour, End: 18 * time.Hour, Location: time.Local}
+	for d := time.Monday; d <= time.Friday; d++ {
+		w.Days[d] = true
+	}
+	return w
+}
+
+// Day returns the length of a working day.
+func (w WorkSchedule) Day() time.Duration {
+	return w.End - w.Start
+}
+
+// FormatWorkingTime formats working time d in working days of length day,
+// e.g. "2d" for 18h of 9-hour days, then in hours and minutes.
+func FormatWorkingTime(d, day time.Duration) string {
+	switch {
+	case day > 0 && d >= day:
+		return fmt.Sprintf("%dd", int(d/day))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
+
+// maxSLADays bounds how far back WorkingTime walks.
+const maxSLADays = 366
+
+// WorkingTime returns how much of [from, to) falls inside working hours.
+func (w WorkSchedule) WorkingTime(from, to time.Time) time.Duration {
+	if !to.After(from) || w.End <= w.Start {
+		return 0
+	}
+	loc := w.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	from, to = from.In(loc), to.In(loc)
+	if to.Sub(from) > maxSLADays*24*time.Hour {
+		from = to.Add(-maxSLADays * 24 * time.Hour)
+	}
+	var total time.Duration
+	y, mo, d := from.Date()
+	for day := time.Date(y, mo, d, 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !w.Days[day.Weekday()] {
+			continue
+		}
+		start := day.Add(w.Start)
+		end := day.Add(w.End)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
+
+type SLALevel int
+
+const (
+	SLANone    SLALevel = iota // not waiting on me
+	SLAOk                      // within the SLA
+	SLAWarning                 // approaching the SLA
+	SLAOverdue                 // past the SLA
+)
+
+// SLA holds the review-time thresholds, measured in working time.
+type SLA struct {
+	Schedule WorkSchedule
+	Warn     time.Duration
+	Overdue  time.Duration
+}
+
+// DefaultSLA is one business day, with a warning at half of it.
+func DefaultSLA() SLA {
+	s := DefaultWorkSchedule()
+	return SLA{Schedule: s, Warn: (s.End - s.Start) / 2, Overdue: s.End - s.Start}
+}
+
+// Waiting returns the working time since my review was requested on pr,
+// or 0 when it is not waiting on me.
+func (s SLA) Waiting(pr PR, now time.Time) time.Duration {
+	if !pr.IsReviewRequested {
+		return 0
+	}
+	return s.Schedule.WorkingTime(pr.WaitingSince(), now)
+}
+
+// Level classifies how long pr has been waiting on me.
+func (s SLA) Level(pr PR, now time.Time) SLALevel {
+	if !pr.IsReviewRequested {
+		return SLANone
+	}
+	waited := s.Waiting(pr, now)
+	switch {
+	case s.Overdue > 0 && waited >= s.Overdue:
+		return SLAOverdue
+	case s.Warn > 0 && waited >= s.Warn:
+		return SLAWarning
+	}
+	return SLAOk
+}
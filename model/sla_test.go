@@ -0,0 +1,81 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestWorkSchedule_WorkingTime(t *testing.T) {
+	w := model.DefaultWorkSchedule()
+	w.Location = time.UTC
+	// 2024-01-05 is a Friday.
+	fri := func(h, m int) time.Time { return time.Date(2024, 1, 5, h, m, 0, 0, time.UTC) }
+	mon := func(h, m int) time.Time { return time.Date(2024, 1, 8, h, m, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     time.Duration
+	}{
+		{"同じ日の勤務時間内", fri(10, 0), fri(12, 30), 150 * time.Minute},
+		{"勤務時間外は数えない", fri(7, 0), fri(20, 0), 9 * time.Hour},
+		{"週末をまたぐ", fri(17, 0), mon(10, 0), 2 * time.Hour},
+		{"週末のみ", time.Date(2024, 1, 6, 10, 0, 0, 0, time.UTC), time.Date(2024, 1, 7, 10, 0, 0, 0, time.UTC), 0},
+		{"逆順は0", fri(12, 0), fri(10, 0), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := w.WorkingTime(tt.from, tt.to); got != tt.want {
+				t.Errorf("WorkingTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSLA_Level(t *testing.T) {
+	sla := model.DefaultSLA()
+	sla.Schedule.Location = time.UTC
+	now := time.Date(2024, 1, 8, 15, 0, 0, 0, time.UTC) // Monday
+
+	tests := []struct {
+		name string
+		pr   model.PR
+		want model.SLALevel
+	}{
+		{"依頼されていない", model.PR{CreatedAt: now.Add(-72 * time.Hour)}, model.SLANone},
+		{"依頼直後", model.PR{IsReviewRequested: true, RequestedAt: now.Add(-time.Hour)}, model.SLAOk},
+		{"半日経過", model.PR{IsReviewRequested: true, RequestedAt: now.Add(-5 * time.Hour)}, model.SLAWarning},
+		{"金曜から待っている", model.PR{IsReviewRequested: true, RequestedAt: time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)}, model.SLAOverdue},
+		{"依頼時刻不明なら作成日時", model.PR{IsReviewRequested: true, CreatedAt: now.Add(-30 * time.Minute)}, model.SLAOk},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sla.Level(tt.pr, now); got != tt.want {
+				t.Errorf("Level() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatWorkingTime(t *testing.T) {
+	day := 9 * time.Hour
+	tests := []struct {
+		name string
+		d    time.Duration
+		want string
+	}{
+		{"分", 30 * time.Minute, "30m"},
+		{"時間", 5 * time.Hour, "5h"},
+		{"1営業日", 9 * time.Hour, "1d"},
+		{"24時間は2営業日", 24 * time.Hour, "2d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := model.FormatWorkingTime(tt.d, day); got != tt.want {
+				t.Errorf("FormatWorkingTime() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
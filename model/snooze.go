@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+type SnoozeKind string
+
+const (
+	SnoozeUntil   SnoozeKind = "until"   // hidden until a date
+	SnoozeCommits SnoozeKind = "commits" // hidden until the head SHA changes
+	SnoozeMention SnoozeKind = "mention" // hidden until someone mentions me
+	SnoozeMute    SnoozeKind = "mute"    // hidden indefinitely
+)
+
+// Snooze hides a PR from the list until its condition fires.
+type Snooze struct {
+	Kind    SnoozeKind `json:"kind"`
+	Since   time.Time  `json:"since"`
+	Until   time.Time  `json:"until,omitzero"`
+	HeadSHA string     `json:"head_sha,omitempty"`
+}
+
+// Expired reports whether the snooze condition has fired for pr.
+// mentioned reports whether the current user was mentioned after s.Since.
+func (s Snooze) Expired(pr PR, mentioned bool, now time.Time) bool {
+	switch s.Kind {
+	case SnoozeUntil:
+		return !now.Before(s.Until)
+	case SnoozeCommits:
+		return pr.HeadSHA != "" && pr.HeadSHA != s.HeadSHA
+	case SnoozeMention:
+		return mentioned
+	}
+	return false
+}
+
+// Label returns a short description for the PR row.
+func (s Snooze) Label() string {
+	switch s.Kind {
+	case SnoozeUntil:
+		return "until " + s.Until.Format("01-02")
+	case SnoozeCommits:
+		return "until new commits"
+	case SnoozeMention:
+		return "until mentioned"
+	case SnoozeMute:
+		return "muted"
+	}
+	return ""
+}
+
+// SnoozeKey identifies a PR's snooze in the local state file.
+func SnoozeKey(repo string, number int) string {
+	return PRKey(repo, number)
+}
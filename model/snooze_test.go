@@ -0,0 +1,35 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestSnooze_Expired(t *testing.T) {
+	now := time.Now()
+	pr := model.PR{Number: 1, HeadSHA: "bbb"}
+
+	tests := []struct {
+		name      string
+		snooze    model.Snooze
+		mentioned bool
+		want      bool
+	}{
+		{"until: 期限前", model.Snooze{Kind: model.SnoozeUntil, Until: now.Add(time.Hour)}, false, false},
+		{"until: 期限後", model.Snooze{Kind: model.SnoozeUntil, Until: now.Add(-time.Hour)}, false, true},
+		{"commits: HEAD 変化なし", model.Snooze{Kind: model.SnoozeCommits, HeadSHA: "bbb"}, false, false},
+		{"commits: HEAD 変化あり", model.Snooze{Kind: model.SnoozeCommits, HeadSHA: "aaa"}, false, true},
+		{"mention: メンションなし", model.Snooze{Kind: model.SnoozeMention}, false, false},
+		{"mention: メンションあり", model.Snooze{Kind: model.SnoozeMention}, true, true},
+		{"mute: 解除されない", model.Snooze{Kind: model.SnoozeMute}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.snooze.Expired(pr, tt.mentioned, now); got != tt.want {
+				t.Errorf("Expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
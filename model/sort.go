@@ -0,0 +1,144 @@
+package model
+
+import "sort"
+
+type SortMode int
+
+const (
+	SortUpdated     SortMode = iota // most recently updated first
+	SortWaiting                     // oldest waiting for my review first
+	SortSize                        // smallest diff first
+	SortCIFailing                   // CI failing first
+	SortReviewState                 // UPD > NEW > CHG > DONE
+	SortNumber                      // highest PR number first
+	SortPriority                    // highest priority score first
+	sortModeCount
+)
+
+func (s SortMode) Label() string {
+	switch s {
+	case SortUpdated:
+		return "Updated"
+	case SortWaiting:
+		return "Waiting"
+	case SortSize:
+		return "Size"
+	case SortCIFailing:
+		return "CI Failing"
+	case SortReviewState:
+		return "Review State"
+	case SortNumber:
+		return "Number"
+	case SortPriority:
+		return "Priority"
+	}
+	return ""
+}
+
+// Key returns the stable identifier used to persist the sort mode.
+func (s SortMode) Key() string {
+	switch s {
+	case SortUpdated:
+		return "updated"
+	case SortWaiting:
+		return "waiting"
+	case SortSize:
+		return "size"
+	case SortCIFailing:
+		return "ci"
+	case SortReviewState:
+		return "state"
+	case SortNumber:
+		return "number"
+	case SortPriority:
+		return "priority"
+	}
+	return ""
+}
+
+func (s SortMode) Next() SortMode {
+	return (s + 1) % sortModeCount
+}
+
+// ParseSortMode returns the SortMode for a key produced by SortMode.Key.
+// Unknown keys fall back to SortUpdated.
+func ParseSortMode(key string) SortMode {
+	for s := SortMode(0); s < sortModeCount; s++ {
+		if s.Key() == key {
+			return s
+		}
+	}
+	return SortUpdated
+}
+
+// ChangedLines returns the total additions and deletions across DiffFiles.
+func (pr PR) ChangedLines() int {
+	n := 0
+	for _, f := range pr.DiffFiles {
+		n += f.Additions + f.Deletions
+	}
+	return n
+}
+
+// SortPRs returns a copy of prs ordered by mode. Ties keep their original
+// order, and reverse flips the ordering. By size, PRs whose files have not
+// been loaded yet sort last either way.
+func SortPRs(prs []PR, mode SortMode, reverse bool) []PR {
+	result := make([]PR, len(prs))
+	copy(result, prs)
+	less := sortLess(mode)
+	sort.SliceStable(result, func(i, j int) bool {
+		if mode == SortSize && result[i].FilesLoaded != result[j].FilesLoaded {
+			return result[i].FilesLoaded
+		}
+		if reverse {
+			return less(result[j], result[i])
+		}
+		return less(result[i], result[j])
+	})
+	return result
+}
+
+func sortLess(mode SortMode) func(a, b PR) bool {
+	switch mode {
+	case SortWaiting:
+		return func(a, b PR) bool { return a.WaitingSince().Before(b.WaitingSince()) }
+	case SortSize:
+		return func(a, b PR) bool { return a.ChangedLines() < b.ChangedLines() }
+	case SortCIFailing:
+		return func(a, b PR) bool { return ciRank(a.CIStatus) < ciRank(b.CIStatus) }
+	case SortReviewState:
+		return func(a, b PR) bool { return reviewStateRank(a.ReviewState) < reviewStateRank(b.ReviewState) }
+	case SortNumber:
+		return func(a, b PR) bool { return a.Number > b.Number }
+	case SortPriority:
+		return func(a, b PR) bool { return a.Priority.Total > b.Priority.Total }
+	}
+	return func(a, b PR) bool { return a.UpdatedAt.After(b.UpdatedAt) }
+}
+
+func ciRank(s CIStatus) int {
+	switch s {
+	case CIStatusFail:
+		return 0
+	case CIStatusPending:
+		return 1
+	case CIStatusPass:
+		return 3
+	}
+	return 2
+}
+
+func reviewStateRank(s ReviewState) int {
+	switch s {
+	case ReviewStateUpd:
+		return 0
+	case ReviewStateNew:
+		return 1
+	case ReviewStateChg:
+		return 2
+	case ReviewStateDone:
+		return 3
+	}
+	return 4
+}
@@ -0,0 +1,71 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestSortPRs(t *testing.T) {
+	now := time.Now()
+	prs := []model.PR{
+		{Number: 1, CreatedAt: now.Add(-1 * time.Hour), UpdatedAt: now.Add(-3 * time.Hour), CIStatus: model.CIStatusPass, ReviewState: model.ReviewStateDone,
+			Priority: model.PriorityScore{Total: 5}, FilesLoaded: true, DiffFiles: []model.DiffFile{{Additions: 50, Deletions: 10}}},
+		{Number: 2, CreatedAt: now.Add(-3 * time.Hour), UpdatedAt: now.Add(-1 * time.Hour), CIStatus: model.CIStatusFail, ReviewState: model.ReviewStateNew},
+		{Number: 3, CreatedAt: now.Add(-2 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour), CIStatus: model.CIStatusPending, ReviewState: model.ReviewStateUpd,
+			Priority: model.PriorityScore{Total: 9}, FilesLoaded: true, DiffFiles: []model.DiffFile{{Additions: 1, Deletions: 1}}},
+	}
+
+	tests := []struct {
+		name     string
+		mode     model.SortMode
+		reverse  bool
+		wantNums []int
+	}{
+		{"SortUpdated: 更新が新しい順", model.SortUpdated, false, []int{2, 3, 1}},
+		{"SortWaiting: 待ち時間が長い順", model.SortWaiting, false, []int{2, 3, 1}},
+		{"SortSize: 差分が小さい順、未取得は最後", model.SortSize, false, []int{3, 1, 2}},
+		{"SortSize reverse: 差分が大きい順、未取得は最後", model.SortSize, true, []int{1, 3, 2}},
+		{"SortCIFailing: CI失敗が先", model.SortCIFailing, false, []int{2, 3, 1}},
+		{"SortReviewState: UPD > NEW > CHG > DONE", model.SortReviewState, false, []int{3, 2, 1}},
+		{"SortNumber: 番号の大きい順", model.SortNumber, false, []int{3, 2, 1}},
+		{"SortNumber reverse: 番号の小さい順", model.SortNumber, true, []int{1, 2, 3}},
+		{"SortPriority: スコアの高い順", model.SortPriority, false, []int{3, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := model.SortPRs(prs, tt.mode, tt.reverse)
+			if len(got) != len(tt.wantNums) {
+				t.Fatalf("SortPRs() len = %d, want %d", len(got), len(tt.wantNums))
+			}
+			for i, pr := range got {
+				if pr.Number != tt.wantNums[i] {
+					t.Errorf("SortPRs()[%d].Number = %d, want %d", i, pr.Number, tt.wantNums[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSortPRs_StableOnTies(t *testing.T) {
+	prs := []model.PR{{Number: 5}, {Number: 9}, {Number: 7}}
+	got := model.SortPRs(prs, model.SortCIFailing, false)
+	for i, want := range []int{5, 9, 7} {
+		if got[i].Number != want {
+			t.Errorf("SortPRs()[%d].Number = %d, want %d", i, got[i].Number, want)
+		}
+	}
+}
+
+func TestParseSortMode(t *testing.T) {
+	for s := model.SortUpdated; s <= model.SortPriority; s++ {
+		if got := model.ParseSortMode(s.Key()); got != s {
+			t.Errorf("ParseSortMode(%q) = %v, want %v", s.Key(), got, s)
+		}
+	}
+	if got := model.ParseSortMode("bogus"); got != model.SortUpdated {
+		t.Errorf("ParseSortMode(bogus) = %v, want SortUpdated", got)
+	}
+}
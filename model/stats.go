@@ -0,0 +1,102 @@
+package model
+
+import "time"
+
+// ReviewActivity is one PR I reviewed, with my reviews on it.
+type ReviewActivity struct {
+	Number      int
+	Title       string
+	RequestedAt time.Time // zero when the request time is unknown
+	CreatedAt   time.Time
+	UpdatedAt   time.Time // when the PR last changed, to tell a cached activity is current
+	Lines       int       // additions + deletions; 0 when unknown
+	Reviews     []Review  // mine only
+}
+
+// WeekLines is the number of lines I reviewed in the week starting at Start.
+type WeekLines struct {
+	Start time.Time
+	Lines int
+}
+
+// ReviewStats summarizes my reviewing over a period.
+type ReviewStats struct {
+	Since              time.Time
+	Reviewed           int // PRs with at least one of my reviews in the period
+	Approved           int
+	ChangesRequested   int
+	Commented          int
+	MedianFirstReview  time.Duration
+	FirstReviewSamples int
+	LinesPerWeek       []WeekLines
+	QueueDepth         int
+}
+
+// ComputeReviewStats aggregates activities fetched from GitHub and my local
+// review history over [since, now]. History fills in PRs the API did not
+// return. queue is the number of PRs currently waiting on me.
+func ComputeReviewStats(acts []ReviewActivity, history []ReviewRecord, repo string, queue int, since, now time.Time) ReviewStats {
+	st := ReviewStats{Since: since, QueueDepth: queue}
+	weeks := make(map[time.Time]int)
+	seen := make(map[int]bool)
+	var firsts []float64
+
+	for _, a := range acts {
+		var first time.Time
+		for _, r := range a.Reviews {
+			if r.CreatedAt.Before(since) || r.CreatedAt.After(now) {
+				continue
+			}
+			switch r.State {
+			case "APPROVED":
+				st.Approved++
+			case "CHANGES_REQUESTED":
+				st.ChangesRequested++
+			case "COMMENTED":
+				st.Commented++
+			}
+			if first.IsZero() || r.CreatedAt.Before(first) {
+				first = r.CreatedAt
+			}
+		}
+		if first.IsZero() {
+			continue
+		}
+		st.Reviewed++
+		seen[a.Number] = true
+		weeks[weekStart(first)] += a.Lines
+		requested := a.RequestedAt
+		if requested.IsZero() {
+			requested = a.CreatedAt
+		}
+		if !requested.IsZero() && first.After(requested) {
+			firsts = append(firsts, float64(first.Sub(requested)))
+		}
+	}
+
+	for _, h := range history {
+		if h.Repo != repo || seen[h.Number] || h.ReviewedAt.Before(since) || h.ReviewedAt.After(now) {
+			continue
+		}
+		seen[h.Number] = true
+		st.Reviewed++
+		weeks[weekStart(h.ReviewedAt)] += h.Lines
+	}
+
+	if len(firsts) > 0 {
+		st.MedianFirstReview = time.Duration(median(firsts))
+		st.FirstReviewSamples = len(firsts)
+	}
+	for w := weekStart(since); !w.After(now); w = w.AddDate(0, 0, 7) {
+		st.LinesPerWeek = append(st.LinesPerWeek, WeekLines{Start: w, Lines: weeks[w]})
+	}
+	return st
+}
+
+// weekStart returns midnight of the Monday starting t's week, in t's location.
+func weekStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
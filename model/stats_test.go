@@ -0,0 +1,60 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestComputeReviewStats(t *testing.T) {
+	// 2024-01-01 is a Monday.
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+	day := func(d, h int) time.Time { return time.Date(2024, 1, d, h, 0, 0, 0, time.UTC) }
+
+	acts := []model.ReviewActivity{
+		{
+			Number:      1,
+			RequestedAt: day(2, 9),
+			Lines:       100,
+			Reviews: []model.Review{
+				{State: "CHANGES_REQUESTED", CreatedAt: day(2, 11)},
+				{State: "APPROVED", CreatedAt: day(3, 10)},
+			},
+		},
+		{
+			Number:    2,
+			CreatedAt: day(9, 8),
+			Lines:     40,
+			Reviews:   []model.Review{{State: "APPROVED", CreatedAt: day(9, 14)}},
+		},
+		{
+			Number:  3,
+			Lines:   999,
+			Reviews: []model.Review{{State: "APPROVED", CreatedAt: day(1, 0).Add(-time.Hour)}},
+		},
+	}
+	history := []model.ReviewRecord{
+		{Repo: "o/r", Number: 1, Lines: 100, ReviewedAt: day(2, 11)},
+		{Repo: "o/r", Number: 4, Lines: 10, ReviewedAt: day(10, 9)},
+		{Repo: "other/repo", Number: 5, Lines: 500, ReviewedAt: day(10, 9)},
+	}
+
+	st := model.ComputeReviewStats(acts, history, "o/r", 3, since, now)
+	if st.Reviewed != 3 {
+		t.Errorf("Reviewed = %d, want 3", st.Reviewed)
+	}
+	if st.Approved != 2 || st.ChangesRequested != 1 {
+		t.Errorf("Approved/ChangesRequested = %d/%d, want 2/1", st.Approved, st.ChangesRequested)
+	}
+	if st.MedianFirstReview != 4*time.Hour || st.FirstReviewSamples != 2 {
+		t.Errorf("MedianFirstReview = %v over %d, want 4h over 2", st.MedianFirstReview, st.FirstReviewSamples)
+	}
+	if len(st.LinesPerWeek) != 2 || st.LinesPerWeek[0].Lines != 100 || st.LinesPerWeek[1].Lines != 50 {
+		t.Errorf("LinesPerWeek = %+v, want [100 50]", st.LinesPerWeek)
+	}
+	if st.QueueDepth != 3 {
+		t.Errorf("QueueDepth = %d, want 3", st.QueueDepth)
+	}
+}
@@ -0,0 +1,270 @@
+// Package patch parses unified diff patches, like those returned by the
+// GitHub API for each file of a PR.
+package patch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind is the kind of a patch line.
+type Kind int
+
+const (
+	Context   Kind = iota // unchanged line
+	Added                 // line starting with +
+	Deleted               // line starting with -
+	Header                // hunk header "@@ -a,b +c,d @@"
+	NoNewline             // "\ No newline at end of file"
+)
+
+// Line is a line of a patch.
+type Line struct {
+	Kind  Kind
+	Text  string // without the leading +, - or space
+	Old   int    // line number in the old file; 0 for added lines
+	New   int    // line number in the new file; 0 for deleted lines
+	Index int    // index among the patch's lines
+}
+
+// Hunk is a hunk of a patch, starting with its header line.
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Section            string // text after the header's closing @@, often the enclosing function
+	Lines              []Line
+}
+
+// Parse splits a patch into hunks and numbers their lines. Lines before the
+// first hunk header are ignored.
+func Parse(p string) []Hunk {
+	var hunks []Hunk
+	var cur *Hunk
+	oldLine, newLine := 0, 0
+	for i, text := range strings.Split(p, "\n") {
+		if strings.HasPrefix(text, "@@") {
+			hunks = append(hunks, parseHeader(text))
+			cur = &hunks[len(hunks)-1]
+			cur.Lines = append(cur.Lines, Line{Kind: Header, Text: text, Index: i})
+			oldLine, newLine = cur.OldStart, cur.NewStart
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		l := Line{Index: i}
+		switch {
+		case strings.HasPrefix(text, "+"):
+			l.Kind, l.Text, l.New = Added, text[1:], newLine
+			newLine++
+		case strings.HasPrefix(text, "-"):
+			l.Kind, l.Text, l.Old = Deleted, text[1:], oldLine
+			oldLine++
+		case strings.HasPrefix(text, `\`):
+			l.Kind, l.Text = NoNewline, text
+		default:
+			l.Kind, l.Text, l.Old, l.New = Context, strings.TrimPrefix(text, " "), oldLine, newLine
+			oldLine++
+			newLine++
+		}
+		cur.Lines = append(cur.Lines, l)
+	}
+	return hunks
+}
+
+// parseHeader reads the ranges of a hunk header. A range without a count
+// spans one line.
+func parseHeader(header string) Hunk {
+	h := Hunk{OldLines: 1, NewLines: 1}
+	if i := strings.Index(header[2:], "@@"); i >= 0 {
+		h.Section = strings.TrimSpace(header[i+4:])
+	}
+	fields := strings.Fields(header)
+	for _, f := range fields[1:] {
+		var start, count *int
+		switch {
+		case strings.HasPrefix(f, "-"):
+			start, count = &h.OldStart, &h.OldLines
+		case strings.HasPrefix(f, "+"):
+			start, count = &h.NewStart, &h.NewLines
+		default:
+			return h
+		}
+		if n, _ := fmt.Sscanf(f[1:], "%d,%d", start, count); n == 1 {
+			*count = 1
+		}
+	}
+	return h
+}
+
+// Row is a row of a side-by-side view of a hunk. Old or New is nil when the
+// row has no line on that side.
+type Row struct {
+	Old, New *Line
+}
+
+// Split aligns the lines of h in old and new columns. Context lines are on
+// both sides; each run of deleted lines is paired, in order, with the added
+// lines that follow it. Headers and "\ No newline" lines are left out.
+func Split(h Hunk) []Row {
+	var rows []Row
+	var dels, adds []*Line
+	flush := func() {
+		for i := range max(len(dels), len(adds)) {
+			var r Row
+			if i < len(dels) {
+				r.Old = dels[i]
+			}
+			if i < len(adds) {
+				r.New = adds[i]
+			}
+			rows = append(rows, r)
+		}
+		dels, adds = nil, nil
+	}
+	for i := range h.Lines {
+		l := &h.Lines[i]
+		switch l.Kind {
+		case Deleted:
+			if len(adds) > 0 {
+				flush()
+			}
+			dels = append(dels, l)
+		case Added:
+			adds = append(adds, l)
+		case Context:
+			flush()
+			rows = append(rows, Row{Old: l, New: l})
+		}
+	}
+	flush()
+	return rows
+}
+
+// Expand returns p with up to above lines of context added before hunk i and
+// up to below lines after it, taken from lines, the new version of the file.
+// Hunks that come to meet are merged. p is returned unchanged when lines is
+// too short to be the file it was made from.
+func Expand(p string, lines []string, i, above, below int) string {
+	hunks := Parse(p)
+	if i < 0 || i >= len(hunks) {
+		return p
+	}
+	if _, end := newRange(hunks[len(hunks)-1]); end-1 > len(lines) {
+		return p
+	}
+	h := &hunks[i]
+	first, end := newRange(*h)
+	if above > 0 {
+		limit := 1
+		if i > 0 {
+			_, limit = newRange(hunks[i-1])
+		}
+		if k := min(above, first-limit); k > 0 {
+			oldFirst, _ := oldRange(*h)
+			ctx := make([]Line, k)
+			for j := range ctx {
+				n := first - k + j
+				ctx[j] = Line{Kind: Context, Text: lines[n-1], Old: oldFirst - k + j, New: n}
+			}
+			h.Lines = append(append([]Line{h.Lines[0]}, ctx...), h.Lines[1:]...)
+			h.OldStart, h.NewStart = oldFirst-k, first-k
+			h.OldLines += k
+			h.NewLines += k
+			h.Section = ""
+		}
+	}
+	if below > 0 {
+		limit := len(lines) + 1
+		if i+1 < len(hunks) {
+			limit, _ = newRange(hunks[i+1])
+		}
+		if k := min(below, limit-end); k > 0 {
+			_, oldEnd := oldRange(*h)
+			first, _ := newRange(*h)
+			for j := range k {
+				h.Lines = append(h.Lines, Line{Kind: Context, Text: lines[end+j-1], Old: oldEnd + j, New: end + j})
+			}
+			oldFirst, _ := oldRange(*h)
+			h.OldStart, h.NewStart = oldFirst, first
+			h.OldLines += k
+			h.NewLines += k
+		}
+	}
+	return format(merge(hunks))
+}
+
+// newRange returns the first new-file line of h and the line after its
+// last. A hunk without new lines starts after the line its header names.
+func newRange(h Hunk) (first, end int) {
+	first = h.NewStart
+	if h.NewLines == 0 {
+		first++
+	}
+	return first, first + h.NewLines
+}
+
+// oldRange is newRange for the old file.
+func oldRange(h Hunk) (first, end int) {
+	first = h.OldStart
+	if h.OldLines == 0 {
+		first++
+	}
+	return first, first + h.OldLines
+}
+
+// merge joins hunks whose new-file ranges meet.
+func merge(hunks []Hunk) []Hunk {
+	var out []Hunk
+	for _, h := range hunks {
+		if n := len(out); n > 0 {
+			prev := &out[n-1]
+			_, end := newRange(*prev)
+			if first, _ := newRange(h); end == first {
+				prev.Lines = append(prev.Lines, h.Lines[1:]...)
+				prev.OldLines += h.OldLines
+				prev.NewLines += h.NewLines
+				continue
+			}
+		}
+		out = append(out, h)
+	}
+	return out
+}
+
+// formatRange writes a range of a hunk header, leaving out a count of one
+// as git does.
+func formatRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// format writes hunks back as a patch.
+func format(hunks []Hunk) string {
+	var b strings.Builder
+	for i, h := range hunks {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@", formatRange(h.OldStart, h.OldLines), formatRange(h.NewStart, h.NewLines))
+		if h.Section != "" {
+			b.WriteString(" " + h.Section)
+		}
+		for _, l := range h.Lines[1:] {
+			b.WriteByte('\n')
+			switch l.Kind {
+			case Added:
+				b.WriteString("+" + l.Text)
+			case Deleted:
+				b.WriteString("-" + l.Text)
+			case Context:
+				b.WriteString(" " + l.Text)
+			default:
+				b.WriteString(l.Text)
+			}
+		}
+	}
+	return b.String()
+}
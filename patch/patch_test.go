@@ -0,0 +1,98 @@
+package patch_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kosuke9809/gh-review/patch"
+)
+
+func TestParse(t *testing.T) {
+	p := "@@ -10,3 +10,3 @@ func main() {\n a\n-b\n+B\n c\n@@ -20 +20,2 @@\n d\n+e\n\\ No newline at end of file"
+	hunks := patch.Parse(p)
+	if len(hunks) != 2 {
+		t.Fatalf("len(hunks) = %d, want 2", len(hunks))
+	}
+	h := hunks[0]
+	if h.OldStart != 10 || h.OldLines != 3 || h.NewStart != 10 || h.NewLines != 3 {
+		t.Errorf("first hunk ranges = %+v", h)
+	}
+	want := []patch.Line{
+		{Kind: patch.Header, Text: "@@ -10,3 +10,3 @@ func main() {", Index: 0},
+		{Kind: patch.Context, Text: "a", Old: 10, New: 10, Index: 1},
+		{Kind: patch.Deleted, Text: "b", Old: 11, Index: 2},
+		{Kind: patch.Added, Text: "B", New: 11, Index: 3},
+		{Kind: patch.Context, Text: "c", Old: 12, New: 12, Index: 4},
+	}
+	if !reflect.DeepEqual(h.Lines, want) {
+		t.Errorf("first hunk lines = %+v, want %+v", h.Lines, want)
+	}
+	h = hunks[1]
+	if h.OldLines != 1 || h.NewLines != 2 {
+		t.Errorf("second hunk ranges = %+v, want a single-line old range", h)
+	}
+	if last := h.Lines[len(h.Lines)-1]; last.Kind != patch.NoNewline || last.Old != 0 || last.New != 0 {
+		t.Errorf("last line = %+v, want an unnumbered no-newline marker", last)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	type pair struct{ old, new string }
+	tests := []struct {
+		name  string
+		patch string
+		want  []pair
+	}{
+		{"変更行を対にする", "@@ -1,3 +1,2 @@\n a\n-b\n-c\n+C\n d", []pair{{"a", "a"}, {"b", "C"}, {"c", ""}, {"d", "d"}}},
+		{"追加のみ", "@@ -0,0 +1,2 @@\n+a\n+b", []pair{{"", "a"}, {"", "b"}}},
+		{"削除のみ", "@@ -1,2 +0,0 @@\n-a\n-b", []pair{{"a", ""}, {"b", ""}}},
+		{"追加の後の削除は別の行", "@@ -1 +1 @@\n+a\n-b", []pair{{"", "a"}, {"b", ""}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []pair
+			for _, r := range patch.Split(patch.Parse(tt.patch)[0]) {
+				var p pair
+				if r.Old != nil {
+					p.old = r.Old.Text
+				}
+				if r.New != nil {
+					p.new = r.New.Text
+				}
+				got = append(got, p)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	lines := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}
+	p := "@@ -4,2 +4,2 @@ func f() {\n 4\n-x\n+5\n@@ -9 +9 @@\n-y\n+9"
+	tests := []struct {
+		name         string
+		lines        []string
+		p            string
+		i            int
+		above, below int
+		want         string
+	}{
+		{"上に広げる", lines, p, 0, 2, 0, "@@ -2,4 +2,4 @@\n 2\n 3\n 4\n-x\n+5\n@@ -9 +9 @@\n-y\n+9"},
+		{"ファイルの先頭まで", lines, p, 0, 10, 0, "@@ -1,5 +1,5 @@\n 1\n 2\n 3\n 4\n-x\n+5\n@@ -9 +9 @@\n-y\n+9"},
+		{"下に広げる", lines, p, 0, 0, 2, "@@ -4,4 +4,4 @@ func f() {\n 4\n-x\n+5\n 6\n 7\n@@ -9 +9 @@\n-y\n+9"},
+		{"隣の塊とつながる", lines, p, 0, 0, 5, "@@ -4,6 +4,6 @@ func f() {\n 4\n-x\n+5\n 6\n 7\n 8\n-y\n+9"},
+		{"ファイルの末尾まで", lines, p, 1, 0, 5, "@@ -4,2 +4,2 @@ func f() {\n 4\n-x\n+5\n@@ -9,2 +9,2 @@\n-y\n+9\n 10"},
+		{"削除のみの塊", lines, "@@ -3,2 +2,0 @@\n-a\n-b", 0, 1, 1, "@@ -2,4 +2,2 @@\n 2\n-a\n-b\n 3"},
+		{"追加のみの塊", lines, "@@ -0,0 +1,2 @@\n+1\n+2", 0, 0, 1, "@@ -1 +1,3 @@\n+1\n+2\n 3"},
+		{"内容が合わない", lines[:5], p, 0, 2, 0, p},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := patch.Expand(tt.p, tt.lines, tt.i, tt.above, tt.below); got != tt.want {
+				t.Errorf("Expand() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
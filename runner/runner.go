@@ -0,0 +1,61 @@
+// Package runner runs shell commands in PR worktrees and streams their
+// output line by line.
+package runner
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// Command is a shell command run in a directory.
+type Command struct {
+	Dir    string
+	Env    []string // added to the current environment
+	Script string   // run with `sh -c`
+}
+
+// Run runs c, calling out with each line of its combined stdout and stderr,
+// and returns its exit code. A non-zero exit is not an error; err is set only
+// when the command could not be run or ctx was cancelled.
+func Run(ctx context.Context, c Command, out func(line string)) (int, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.Script)
+	cmd.Dir = c.Dir
+	cmd.Env = append(os.Environ(), c.Env...)
+	// Children that keep the output open must not block Wait after a cancel.
+	cmd.WaitDelay = time.Second
+	pr, pw := io.Pipe()
+	cmd.Stdout, cmd.Stderr = pw, pw
+	if err := cmd.Start(); err != nil {
+		return -1, err
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sc := bufio.NewScanner(pr)
+		sc.Buffer(make([]byte, 64*1024), 1024*1024)
+		for sc.Scan() {
+			out(sc.Text())
+		}
+		// Keep draining so the command never blocks on a full pipe.
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+	err := cmd.Wait()
+	pw.Close()
+	<-done
+	if ctx.Err() != nil {
+		return -1, ctx.Err()
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return -1, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,53 @@
+package runner_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/runner"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		cmd      runner.Command
+		want     []string
+		wantCode int
+	}{
+		{"stdoutとstderrを行ごとに", runner.Command{Dir: dir, Script: "echo one; echo two >&2"}, []string{"one", "two"}, 0},
+		{"環境変数と作業ディレクトリ", runner.Command{Dir: dir, Env: []string{"PR_NUMBER=42"}, Script: `echo "$PR_NUMBER" && test "$(pwd -P)" = "$(cd "` + dir + `" && pwd -P)" && echo here`}, []string{"42", "here"}, 0},
+		{"終了コード", runner.Command{Dir: dir, Script: "echo failing; exit 3"}, []string{"failing"}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			code, err := runner.Run(context.Background(), tt.cmd, func(line string) { got = append(got, line) })
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if code != tt.wantCode {
+				t.Errorf("Run() code = %d, want %d", code, tt.wantCode)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_Cancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := runner.Run(ctx, runner.Command{Dir: t.TempDir(), Script: "sleep 10"}, func(string) {})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() error = %v, want deadline exceeded", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("Run() took %v after cancel", time.Since(start))
+	}
+}
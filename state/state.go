@@ -0,0 +1,117 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	ghconfig "github.com/cli/go-gh/v2/pkg/config"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+// State is the local, per-user data gh-review keeps between sessions.
+type State struct {
+	Sort        string `json:"sort,omitempty"`
+	SortReverse bool   `json:"sort_reverse,omitempty"`
+
+	// Snoozes is keyed by model.SnoozeKey.
+	Snoozes map[string]model.Snooze `json:"snoozes,omitempty"`
+
+	// ReviewPending accumulates time spent on a PR's detail screen until my
+	// review on it shows up; it is keyed by model.PRKey.
+	ReviewPending map[string]PendingReview `json:"review_pending,omitempty"`
+	// ReviewHistory holds my completed reviews, oldest first.
+	ReviewHistory []model.ReviewRecord `json:"review_history,omitempty"`
+
+	// LocalRuns holds the last command run in each PR's worktree; it is
+	// keyed by model.PRKey.
+	LocalRuns map[string]model.LocalRun `json:"local_runs,omitempty"`
+}
+
+// PendingReview is the time spent on a PR I have not yet reviewed.
+type PendingReview struct {
+	Since time.Time     `json:"since"`
+	Spent time.Duration `json:"spent"`
+}
+
+// MaxReviewHistory caps the number of ReviewHistory records kept.
+const MaxReviewHistory = 500
+
+// AddReview appends r to the history, dropping the oldest records beyond
+// MaxReviewHistory.
+func (st *State) AddReview(r model.ReviewRecord) {
+	st.ReviewHistory = append(st.ReviewHistory, r)
+	if n := len(st.ReviewHistory) - MaxReviewHistory; n > 0 {
+		st.ReviewHistory = append([]model.ReviewRecord(nil), st.ReviewHistory[n:]...)
+	}
+}
+
+// Clone returns a copy of st that shares no maps with the original, so it
+// can be saved from another goroutine.
+func (st State) Clone() State {
+	st.Snoozes = maps.Clone(st.Snoozes)
+	st.ReviewPending = maps.Clone(st.ReviewPending)
+	st.ReviewHistory = slices.Clone(st.ReviewHistory)
+	st.LocalRuns = maps.Clone(st.LocalRuns)
+	return st
+}
+
+// Store reads and writes State at a fixed path.
+type Store struct {
+	path string
+}
+
+// NewStore returns a Store backed by the file at path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns the state file location under gh's state directory.
+func DefaultPath() string {
+	return filepath.Join(ghconfig.StateDir(), "gh-review", "state.json")
+}
+
+// Path returns the file the store reads and writes.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load reads the state file. A missing file yields an empty State.
+func (s *Store) Load() (State, error) {
+	var st State
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, fmt.Errorf("read state %s: %w", s.path, err)
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return st, fmt.Errorf("parse state %s: %w", s.path, err)
+	}
+	return st, nil
+}
+
+// Save writes st atomically, creating the parent directory if needed.
+func (s *Store) Save(st State) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write state %s: %w", s.path, err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("write state %s: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package state_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/model"
+	"github.com/kosuke9809/gh-review/state"
+)
+
+func TestStore_LoadMissing(t *testing.T) {
+	s := state.NewStore(filepath.Join(t.TempDir(), "state.json"))
+	st, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(st, state.State{}) {
+		t.Errorf("Load() = %+v, want zero State", st)
+	}
+}
+
+func TestStore_SaveLoad(t *testing.T) {
+	s := state.NewStore(filepath.Join(t.TempDir(), "nested", "state.json"))
+	want := state.State{
+		Sort:        "size",
+		SortReverse: true,
+		Snoozes: map[string]model.Snooze{
+			model.SnoozeKey("o/r", 1): {Kind: model.SnoozeMute, Since: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		},
+	}
+	if err := s.Save(want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestState_Clone(t *testing.T) {
+	st := state.State{Snoozes: map[string]model.Snooze{"o/r#1": {Kind: model.SnoozeMute}}}
+	c := st.Clone()
+	delete(c.Snoozes, "o/r#1")
+	if len(st.Snoozes) != 1 {
+		t.Error("Clone() shares the Snoozes map with the original")
+	}
+}
+
+func TestState_AddReviewCapsHistory(t *testing.T) {
+	var st state.State
+	for i := 0; i < state.MaxReviewHistory+5; i++ {
+		st.AddReview(model.ReviewRecord{Number: i})
+	}
+	if len(st.ReviewHistory) != state.MaxReviewHistory {
+		t.Fatalf("len(ReviewHistory) = %d, want %d", len(st.ReviewHistory), state.MaxReviewHistory)
+	}
+	if st.ReviewHistory[0].Number != 5 {
+		t.Errorf("oldest record = #%d, want #5", st.ReviewHistory[0].Number)
+	}
+}
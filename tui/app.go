@@ -2,19 +2,27 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
 	gogithub "github.com/google/go-github/v68/github"
+	"github.com/kosuke9809/gh-review/config"
+	"github.com/kosuke9809/gh-review/coverage"
 	"github.com/kosuke9809/gh-review/git"
 	"github.com/kosuke9809/gh-review/github"
+	"github.com/kosuke9809/gh-review/highlight"
 	"github.com/kosuke9809/gh-review/model"
+	"github.com/kosuke9809/gh-review/state"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -27,6 +35,12 @@ type screen int
 const (
 	screenList   screen = iota
 	screenDetail
+	screenStats
+	screenReviewers
+	screenWorktrees
+	screenLog
+	screenRun
+	screenTests
 )
 
 type detailSubTab int
@@ -38,8 +52,17 @@ const (
 
 
 type fetchedMsg struct {
-	prs []model.PR
-	err error
+	prs         []model.PR
+	mentioned   map[int]bool        // PRs snoozed until a mention that have one
+	requestedAt map[int]requestTime // review request times, cached across refreshes
+	err         error
+}
+
+// requestTime is when my review was requested on a PR, as looked up when the
+// PR was last updated at updated.
+type requestTime struct {
+	at      time.Time
+	updated time.Time
 }
 
 type detailFetchedMsg struct {
@@ -52,55 +75,188 @@ type detailFetchedMsg struct {
 	err      error
 }
 
+type statsFetchedMsg struct {
+	since      time.Time
+	activities []model.ReviewActivity
+	err        error
+}
+
+// reviewsFetchedMsg carries the reviews and review requests of the open PRs
+// for the Reviewers screen.
+type reviewsFetchedMsg struct {
+	reviewers map[int]prReviewers
+	err       error
+}
+
+// worktreesLoadedMsg carries the worktrees under .worktrees together with
+// the state of their PRs.
+type worktreesLoadedMsg struct {
+	rows []worktreeRow
+	err  error
+}
+
+// worktreesMigratedMsg reports how many worktrees were moved to the
+// configured layout.
+type worktreesMigratedMsg struct {
+	moved int
+	err   error
+}
+
+// worktreeCreatedMsg reports that a PR's worktree was created at path.
+type worktreeCreatedMsg struct {
+	prNumber int
+	path     string
+}
+
+// worktreeUpdatedMsg reports the outcome of moving a PR's worktree to the
+// PR's latest head.
+type worktreeUpdatedMsg struct {
+	prNumber int
+	path     string
+	update   git.WorktreeUpdate
+	err      error
+}
+
 type tickMsg time.Time
 
+type stateSavedMsg struct {
+	err error
+}
+
 // AppModel is the root bubbletea model.
 type AppModel struct {
-	screen        screen
-	detailSubTab  detailSubTab
-	selectedPR    *model.PR
-	filter        model.PRFilter
-	prsTab        prsTabModel
-	detailTab     detailTabModel
-	diffTab       diffTabModel
-	allPRs        []model.PR
-	prs           []model.PR
-	loading       bool
-	err           error
-	lastSync      time.Time
-	repoName      string
-	repoOwner     string
-	repoRepo      string
-	repoRoot      string
-	currentUser   string
-	ghClient      *gogithub.Client
-	width         int
-	height        int
-	spinner       spinner.Model
-	loadingDetail bool
-}
-
-// New creates a new AppModel.
-func New(owner, repo, repoRoot, currentUser string, client *gogithub.Client, width, height int) AppModel {
+	screen           screen
+	detailSubTab     detailSubTab
+	selectedPR       *model.PR
+	filter           model.PRFilter
+	customFilters    []model.CustomFilter
+	weights          model.PriorityWeights
+	sla              model.SLA
+	requestedAt      map[int]requestTime
+	overdueCount     int
+	sortMode         model.SortMode
+	sortReverse      bool
+	prsTab           prsTabModel
+	detailTab        detailTabModel
+	diffTab          diffTabModel
+	statsTab         statsTabModel
+	statsPeriod      int // index into statsPeriods
+	loadingStats     bool
+	statsCache       map[int]model.ReviewActivity // PRs I reviewed, by number
+	statsFrom        time.Time                    // how far back statsCache is complete
+	reviewersTab     reviewersTabModel
+	loadingReviewers bool
+	reviewerFilter   *model.ReviewerLoad // drill-down from the Reviewers screen
+	reviewers        map[int]prReviewers // fetched for the Reviewers screen, by PR number
+	wtLayout         git.Layout          // where PR worktrees are created
+	worktreesTab     worktreesTabModel
+	loadingWorktrees bool
+	removeConfirm    *removeConfirm
+	hooks            config.Hooks
+	hookStatus       map[int]model.HookStatus
+	hookRuns         map[int]hookRun // running hooks by PR
+	logPanel         logPanelModel
+	runTab           runTabModel
+	runFrom          screen // where Esc leaves the Run screen to
+	testsTab         goTestTabModel
+	profiles         map[int]*coverage.Profile // coverage overlays by PR
+	coverFile        string                    // coverage profile in worktrees; empty looks for the usual names
+	localDiffs       map[int]localDiff
+	mergeBases       map[string]string // merge bases by mergeBaseKey
+	diffOpts         git.DiffOptions
+	diffContext      int                    // configured context lines, restored after a full-file diff
+	diffLocal        bool                   // compute diffs with git when the PR's commits are local
+	pager            string                 // formats patches in the Diff tab; empty uses the built-in colors
+	pagerOff         bool                   // pager toggled off with |
+	pagerFailed      bool                   // a pager failure was reported; later ones are not
+	difftool         string                 // shows a whole PR diff; defaultDifftool when empty
+	highlighter      *highlight.Highlighter // nil when syntax highlighting is off
+	diffSplit        bool                   // side-by-side diffs, toggled with s
+	lastRemoved      *removedWorktrees
+	allPRs           []model.PR
+	prs              []model.PR
+	loading          bool
+	err              error
+	lastSync         time.Time
+	notice           string // result of the last action, cleared on the next key
+	repoName         string
+	repoOwner        string
+	repoRepo         string
+	repoRoot         string
+	currentUser      string
+	ghClient         *gogithub.Client
+	width            int
+	height           int
+	spinner          spinner.Model
+	loadingDetail    bool
+	store            *state.Store
+	state            state.State
+
+	detailOpenedAt time.Time
+
+	showSnoozed     bool
+	snoozedCount    int
+	snoozeMenu      bool
+	dateInputActive bool
+	dateInput       textinput.Model
+	lineInputActive bool
+	lineInput       textinput.Model
+	helpOpen        bool
+}
+
+// New creates a new AppModel. st is the previously saved state, which is
+// written back to store whenever a persisted setting changes.
+func New(owner, repo, repoRoot, currentUser string, client *gogithub.Client, cfg config.Config, store *state.Store, st state.State, width, height int) AppModel {
 	inner := width - 2
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
 	sp.Style = lipgloss.NewStyle().Foreground(colorGreen)
+	sla := cfg.ReviewSLA()
 	return AppModel{
-		screen:      screenList,
-		prsTab:      newPRsTab(inner, height),
-		detailTab:   newDetailTab(inner, height),
-		diffTab:     newDiffTab(inner, height),
-		loading:     true,
-		repoName:    owner + "/" + repo,
-		repoOwner:   owner,
-		repoRepo:    repo,
-		repoRoot:    repoRoot,
-		currentUser: currentUser,
-		ghClient:    client,
-		width:       width,
-		height:      height,
-		spinner:     sp,
+		screen:        screenList,
+		prsTab:        newPRsTab(inner, height, sla.Schedule),
+		detailTab:     newDetailTab(inner, height, sla.Schedule),
+		diffTab:       newDiffTab(inner, height),
+		statsTab:      newStatsTab(inner, height),
+		statsPeriod:   1,
+		reviewersTab:  newReviewersTab(inner, height),
+		worktreesTab:  newWorktreesTab(inner, height),
+		loading:       true,
+		repoName:      owner + "/" + repo,
+		repoOwner:     owner,
+		repoRepo:      repo,
+		repoRoot:      repoRoot,
+		currentUser:   currentUser,
+		ghClient:      client,
+		width:         width,
+		height:        height,
+		spinner:       sp,
+		customFilters: cfg.CustomFilters(),
+		weights:       cfg.PriorityWeights(),
+		sla:           sla,
+		wtLayout:      cfg.WorktreeLayout(),
+		hooks:         cfg.Repo(owner + "/" + repo).Hooks,
+		hookStatus:    make(map[int]model.HookStatus),
+		hookRuns:      make(map[int]hookRun),
+		logPanel:      newLogPanel(inner, height-4),
+		runTab:        newRunTab(inner, height-4, runPresets(cfg.Repo(owner+"/"+repo))),
+		testsTab:      newGoTestTab(inner, height-4),
+		profiles:      make(map[int]*coverage.Profile),
+		coverFile:     cfg.Repo(owner + "/" + repo).Coverage,
+		localDiffs:    make(map[int]localDiff),
+		mergeBases:    make(map[string]string),
+		statsCache:    make(map[int]model.ReviewActivity),
+		diffOpts:      cfg.DiffOptions(),
+		diffContext:   cfg.Diff.Context,
+		diffLocal:     cfg.Diff.Local,
+		pager:         cfg.Diff.Pager,
+		difftool:      cfg.Diff.Difftool,
+		highlighter:   cfg.Highlighter(),
+		diffSplit:     cfg.Diff.Split,
+		sortMode:      model.ParseSortMode(st.Sort),
+		sortReverse:   st.SortReverse,
+		store:         store,
+		state:         st,
 	}
 }
 
@@ -115,6 +271,8 @@ func tickCmd() tea.Cmd {
 }
 
 func (m AppModel) fetchCmd() tea.Cmd {
+	mentionSince := m.mentionSnoozes()
+	knownRequestedAt := maps.Clone(m.requestedAt)
 	return func() tea.Msg {
 		ctx := context.Background()
 		ghPRs, err := github.FetchPRs(ctx, m.ghClient, m.repoOwner, m.repoRepo, m.currentUser)
@@ -123,27 +281,76 @@ func (m AppModel) fetchCmd() tea.Cmd {
 		}
 		prs := make([]model.PR, 0, len(ghPRs))
 		for _, ghPR := range ghPRs {
-			wtPath := git.WorktreePath(m.repoRoot, int(ghPR.GetNumber()))
-			hasWt := git.WorktreeExists(m.repoRoot, int(ghPR.GetNumber()))
+			var labels, reviewers, teams []string
+			for _, l := range ghPR.Labels {
+				labels = append(labels, l.GetName())
+			}
+			for _, r := range ghPR.RequestedReviewers {
+				reviewers = append(reviewers, r.GetLogin())
+			}
+			for _, t := range ghPR.RequestedTeams {
+				teams = append(teams, t.GetSlug())
+			}
 			prs = append(prs, model.PR{
-				Number:            int(ghPR.GetNumber()),
-				Title:             ghPR.GetTitle(),
-				Author:            ghPR.GetUser().GetLogin(),
-				BaseRef:           ghPR.GetBase().GetRef(),
-				HeadRef:           ghPR.GetHead().GetRef(),
-				HeadSHA:           ghPR.GetHead().GetSHA(),
-				Body:              ghPR.GetBody(),
-				CreatedAt:         ghPR.GetCreatedAt().Time,
-				UpdatedAt:         ghPR.GetUpdatedAt().Time,
-				HTMLURL:           ghPR.GetHTMLURL(),
-				CIStatus:          model.CIStatusUnknown,
-				IsReviewRequested: github.IsReviewRequested(ghPR, m.currentUser),
-				HasWorktree:       hasWt,
-				WorktreePath:      wtPath,
-				DetailLoaded:      false,
+				Number:             int(ghPR.GetNumber()),
+				Title:              ghPR.GetTitle(),
+				Author:             ghPR.GetUser().GetLogin(),
+				BaseRef:            ghPR.GetBase().GetRef(),
+				BaseSHA:            ghPR.GetBase().GetSHA(),
+				HeadRef:            ghPR.GetHead().GetRef(),
+				HeadSHA:            ghPR.GetHead().GetSHA(),
+				HeadRepo:           ghPR.GetHead().GetRepo().GetFullName(),
+				HeadCloneURL:       ghPR.GetHead().GetRepo().GetCloneURL(),
+				HeadSSHURL:         ghPR.GetHead().GetRepo().GetSSHURL(),
+				HeadEditable:       ghPR.GetMaintainerCanModify(),
+				Body:               ghPR.GetBody(),
+				CreatedAt:          ghPR.GetCreatedAt().Time,
+				UpdatedAt:          ghPR.GetUpdatedAt().Time,
+				HTMLURL:            ghPR.GetHTMLURL(),
+				Labels:             labels,
+				RequestedReviewers: reviewers,
+				RequestedTeams:     teams,
+				CIStatus:           model.CIStatusUnknown,
+				IsReviewRequested:  github.IsReviewRequested(ghPR, m.currentUser),
+				DetailLoaded:       false,
 			})
 		}
-		return fetchedMsg{prs: prs}
+		// Worktree detection is best-effort: without git, PRs just show none.
+		if wts, err := git.PRWorktrees(m.repoRoot, m.wtLayout); err == nil {
+			prs = withWorktrees(prs, wts, branchStatus)
+		}
+		// The request time only changes when I am re-requested, which updates
+		// the PR, so it is looked up again only after the PR changed. Lookups
+		// are best-effort and fall back to the PR's creation time.
+		requestedAt := make(map[int]requestTime)
+		for i := range prs {
+			if !prs[i].IsReviewRequested {
+				continue
+			}
+			t, ok := knownRequestedAt[prs[i].Number]
+			if !ok || !t.updated.Equal(prs[i].UpdatedAt) {
+				at, err := github.FetchRequestedAt(ctx, m.ghClient, m.repoOwner, m.repoRepo, prs[i].Number, m.currentUser)
+				if err != nil {
+					continue
+				}
+				t = requestTime{at: at, updated: prs[i].UpdatedAt}
+			}
+			requestedAt[prs[i].Number] = t
+			prs[i].RequestedAt = t.at
+		}
+		// Mention checks are best-effort: a failed lookup is retried on the
+		// next refresh rather than failing the whole sync.
+		mentioned := make(map[int]bool)
+		for _, pr := range prs {
+			since, ok := mentionSince[pr.Number]
+			if !ok {
+				continue
+			}
+			if hit, err := github.MentionedSince(ctx, m.ghClient, m.repoOwner, m.repoRepo, pr.Number, m.currentUser, since); err == nil && hit {
+				mentioned[pr.Number] = true
+			}
+		}
+		return fetchedMsg{prs: prs, mentioned: mentioned, requestedAt: requestedAt}
 	}
 }
 
@@ -192,18 +399,69 @@ func (m AppModel) detailFetchCmd(pr model.PR) tea.Cmd {
 	}
 }
 
+func (m AppModel) statsFetchCmd(since time.Time) tea.Cmd {
+	known := maps.Clone(m.statsCache)
+	return func() tea.Msg {
+		acts, err := github.FetchReviewActivity(context.Background(), m.ghClient, m.repoOwner, m.repoRepo, m.currentUser, since, known)
+		return statsFetchedMsg{since: since, activities: acts, err: err}
+	}
+}
+
+// reviewsFetchCmd fetches when each requested reviewer of the open PRs was
+// requested, and the reviews of those whose details have not been loaded.
+func (m AppModel) reviewsFetchCmd() tea.Cmd {
+	prs := slices.Clone(m.allPRs)
+	return func() tea.Msg {
+		result := make([]prReviewers, len(prs))
+		eg, ctx := errgroup.WithContext(context.Background())
+		eg.SetLimit(4)
+		for i, pr := range prs {
+			eg.Go(func() error {
+				var err error
+				if !pr.DetailLoaded {
+					if result[i].reviews, err = github.FetchReviews(ctx, m.ghClient, m.repoOwner, m.repoRepo, pr.Number); err != nil {
+						return err
+					}
+				}
+				if len(pr.RequestedReviewers)+len(pr.RequestedTeams) > 0 {
+					result[i].requests, err = github.FetchReviewRequests(ctx, m.ghClient, m.repoOwner, m.repoRepo, pr.Number)
+				}
+				return err
+			})
+		}
+		if err := eg.Wait(); err != nil {
+			return reviewsFetchedMsg{err: err}
+		}
+		reviewers := make(map[int]prReviewers, len(prs))
+		for i, pr := range prs {
+			reviewers[pr.Number] = result[i]
+		}
+		return reviewsFetchedMsg{reviewers: reviewers}
+	}
+}
+
 func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case tea.WindowSizeMsg:
 		m.width, m.height = msg.Width, msg.Height
 		inner := msg.Width - 2
-		m.prsTab = newPRsTab(inner, msg.Height).SetPRs(m.prs)
-		m.detailTab = newDetailTab(inner, msg.Height)
-		m.diffTab = newDiffTab(inner, msg.Height)
+		m.prsTab = newPRsTab(inner, msg.Height, m.sla.Schedule).SetPRs(m.prs)
+		m.detailTab = newDetailTab(inner, msg.Height, m.sla.Schedule)
+		dt := newDiffTab(inner, msg.Height)
+		dt.colored, dt.contents, dt.expanded = m.diffTab.colored, m.diffTab.contents, m.diffTab.expanded
+		m.diffTab = dt
+		m.statsTab = newStatsTab(inner, msg.Height).SetStats(m.statsTab.stats)
+		m.reviewersTab = newReviewersTab(inner, msg.Height).SetLoads(m.reviewersTab.loads)
+		wt := newWorktreesTab(inner, msg.Height)
+		wt.marked, wt.status = m.worktreesTab.marked, m.worktreesTab.status
+		m.worktreesTab = wt.SetRows(m.worktreesTab.rows)
+		m.logPanel = m.logPanel.Resize(inner, msg.Height-4)
+		m.runTab = m.runTab.Resize(inner, msg.Height-4)
+		m.testsTab = m.testsTab.Resize(inner, msg.Height-4)
 		if m.selectedPR != nil {
 			m.detailTab = m.detailTab.SetPR(m.selectedPR)
-			m.diffTab = m.diffTab.SetFiles(m.selectedPR.DiffFiles)
+			m = m.refreshDiff()
 		}
 
 	case fetchedMsg:
@@ -213,23 +471,30 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.err
 		} else {
 			m.err = nil
-			m.allPRs = msg.prs
+			m.allPRs = withFiles(msg.prs, m.allPRs)
+			m.requestedAt = msg.requestedAt
+			var cmds []tea.Cmd
+			var expired bool
+			if m, expired = m.expireSnoozes(msg.mentioned); expired {
+				cmds = append(cmds, m.saveStateCmd())
+			}
 			m = m.applyFilter()
 			// Re-fetch details for currently viewed PR
 			if m.selectedPR != nil {
 				for _, pr := range m.allPRs {
 					if pr.Number == m.selectedPR.Number {
-						pr := pr
+						pr := m.annotate(pr, time.Now())
 						m.selectedPR = &pr
 						m.loadingDetail = false
 						if !pr.DetailLoaded {
 							m.loadingDetail = true
-							return m, m.detailFetchCmd(pr)
+							cmds = append(cmds, m.detailFetchCmd(pr))
 						}
 						break
 					}
 				}
 			}
+			return m, tea.Batch(cmds...)
 		}
 
 	case detailFetchedMsg:
@@ -237,6 +502,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.err
 			m.loadingDetail = false
 		} else {
+			var recorded bool
 			for i, pr := range m.allPRs {
 				if pr.Number == msg.prNumber {
 					m.allPRs[i].Reviews = msg.reviews
@@ -245,20 +511,179 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.allPRs[i].Comments = msg.comments
 					m.allPRs[i].DiffFiles = msg.files
 					m.allPRs[i].DetailLoaded = true
+					m.allPRs[i].FilesLoaded = true
 					m.allPRs[i].ReviewState = github.CalcReviewState(m.currentUser, msg.reviews, m.allPRs[i].UpdatedAt)
+					m, recorded = m.recordReview(m.allPRs[i])
 					// Only update UI and clear loading if this is the PR being viewed
 					if m.selectedPR != nil && m.selectedPR.Number == msg.prNumber {
-						updated := m.allPRs[i]
+						updated := m.annotate(m.allPRs[i], time.Now())
 						m.selectedPR = &updated
 						m.detailTab = m.detailTab.SetPR(m.selectedPR)
-						m.diffTab = m.diffTab.SetFiles(m.selectedPR.DiffFiles)
+						m = m.refreshDiff()
 						m.loadingDetail = false
 					}
 					break
 				}
 			}
 			m = m.applyFilter()
+			if recorded {
+				return m, m.saveStateCmd()
+			}
+		}
+
+	case statsFetchedMsg:
+		m = m.applyStats(msg)
+		return m, nil
+
+	case reviewsFetchedMsg:
+		m.loadingReviewers = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.reviewers = msg.reviewers
+		m = m.applyFilter()
+		prs := make([]model.PR, len(m.allPRs))
+		for i, pr := range m.allPRs {
+			prs[i] = m.withReviewers(pr)
+		}
+		m.reviewersTab = m.reviewersTab.SetLoads(model.ReviewerLoads(prs))
+		return m, nil
+
+	case worktreesLoadedMsg:
+		m.loadingWorktrees = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
 		}
+		m.worktreesTab = m.worktreesTab.SetRows(msg.rows)
+		return m, nil
+
+	case removalInspectedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.removeConfirm = &msg.confirm
+		return m, nil
+
+	case worktreesRemovedMsg:
+		m.notice = removedNotice(msg)
+		m.worktreesTab.status = m.notice
+		m.lastRemoved = nil
+		if len(msg.removed) > 0 {
+			m.lastRemoved = &removedWorktrees{targets: msg.removed, at: time.Now()}
+		}
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		m.loading = true
+		if m.screen == screenWorktrees {
+			m.loadingWorktrees = true
+			return m, tea.Batch(m.worktreesLoadCmd(), m.fetchCmd())
+		}
+		return m, m.fetchCmd()
+
+	case worktreesMigratedMsg:
+		m.worktreesTab.status = fmt.Sprintf("moved %d worktrees", msg.moved)
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		m.loading = true
+		m.loadingWorktrees = true
+		return m, tea.Batch(m.worktreesLoadCmd(), m.fetchCmd())
+
+	case worktreesRestoredMsg:
+		m.notice = fmt.Sprintf("restored %d worktrees", msg.restored)
+		m.worktreesTab.status = m.notice
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		m.loading = true
+		if m.screen == screenWorktrees {
+			m.loadingWorktrees = true
+			return m, tea.Batch(m.worktreesLoadCmd(), m.fetchCmd())
+		}
+		return m, m.fetchCmd()
+
+	case worktreeCreatedMsg:
+		m.loading = true
+		cmds := []tea.Cmd{m.fetchCmd()}
+		if pr, ok := m.prByNumber(msg.prNumber); ok {
+			var cmd tea.Cmd
+			m, cmd = m.startHooks(pr, msg.path, "post_create", m.hooks.PostCreate)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+
+	case hookOutputMsg:
+		m.logPanel = m.logPanel.Append(fmt.Sprintf("[#%d] %s", msg.prNumber, msg.line))
+		return m, waitForMsg(msg.ch)
+
+	case hookDoneMsg:
+		m = m.finishHooks(msg)
+		return m, nil
+
+	case runOutputMsg:
+		m.runTab.output = m.runTab.output.Append(msg.line)
+		return m, waitForMsg(msg.ch)
+
+	case runDoneMsg:
+		return m.finishRun(msg)
+
+	case testsPlannedMsg, testsOutputMsg, testsDoneMsg:
+		return m.updateGoTests(msg)
+
+	case coverageLoadedMsg:
+		m = m.applyCoverage(msg)
+		return m, nil
+
+	case localDiffMsg:
+		m = m.applyLocalDiff(msg)
+		return m, m.diffTab.PageCmd()
+
+	case pagedMsg:
+		m = m.applyPaged(msg)
+		return m, nil
+
+	case fileContentMsg:
+		m = m.applyFileContent(msg)
+		return m, nil
+
+	case mergeBaseMsg:
+		return m.applyMergeBase(msg)
+
+	case difftoolMsg:
+		return m.openDifftool(msg)
+
+	case difftoolDoneMsg:
+		if msg.err != nil {
+			m.notice = fmt.Sprintf("difftool: %v", msg.err)
+		}
+		return m, nil
+
+	case worktreeUpdatedMsg:
+		if msg.err != nil {
+			m.notice = fmt.Sprintf("#%d not updated: %v", msg.prNumber, msg.err)
+			return m, nil
+		}
+		u := msg.update
+		switch {
+		case u.UpToDate():
+			m.notice = fmt.Sprintf("#%d worktree already at %s", msg.prNumber, git.ShortSHA(u.New))
+		case u.ForcePushed:
+			m.notice = fmt.Sprintf("#%d worktree reset %s→%s (force-pushed, %d new commits)", msg.prNumber, git.ShortSHA(u.Old), git.ShortSHA(u.New), u.Commits)
+		default:
+			m.notice = fmt.Sprintf("#%d worktree updated %s→%s (%d new commits)", msg.prNumber, git.ShortSHA(u.Old), git.ShortSHA(u.New), u.Commits)
+		}
+		m.loading = true
+		cmds := []tea.Cmd{m.fetchCmd()}
+		if pr, ok := m.prByNumber(msg.prNumber); ok && !u.UpToDate() {
+			var cmd tea.Cmd
+			m, cmd = m.startHooks(pr, msg.path, "post_update", m.hooks.PostUpdate)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -269,70 +694,382 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = true
 		return m, tea.Batch(m.fetchCmd(), tickCmd())
 
+	case stateSavedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.dateInputActive {
+			return m.updateDateInput(msg)
+		}
+		if m.lineInputActive {
+			return m.updateLineInput(msg)
+		}
+		if m.snoozeMenu {
+			return m.updateSnoozeMenu(msg)
+		}
+		if m.removeConfirm != nil {
+			return m.updateRemoveConfirm(msg)
+		}
+		if m.screen == screenRun && m.runTab.editing {
+			return m.updateCommandInput(msg)
+		}
+		if m.helpOpen {
+			m.helpOpen = false
+			if s := msg.String(); s != "q" && s != "ctrl+c" {
+				return m, nil
+			}
+		}
+		m.notice = ""
 		switch msg.String() {
 		case "q", "ctrl+c":
+			if m.screen == screenRun && m.runTab.running != "" {
+				if msg.String() == "ctrl+c" {
+					m.runTab.Cancel()
+					return m, nil
+				}
+				m.runTab.Cancel()
+			}
+			if m.screen == screenTests && m.testsTab.cancel != nil {
+				if msg.String() == "ctrl+c" {
+					m.testsTab.Cancel()
+					return m, nil
+				}
+				m.testsTab.Cancel()
+			}
+			if m, changed := m.leaveDetail(time.Now()); changed {
+				return m, tea.Sequence(m.saveStateCmd(), tea.Quit)
+			}
 			return m, tea.Quit
+		case "?":
+			m.helpOpen = true
+			return m, nil
 		case "esc", "b":
+			if m.screen == screenStats || m.screen == screenReviewers || m.screen == screenWorktrees || m.screen == screenLog {
+				m.screen = screenList
+				return m, nil
+			}
+			if m.screen == screenRun {
+				m.screen = m.runFrom
+				return m, nil
+			}
+			if m.screen == screenTests {
+				m.screen = screenDetail
+				return m, nil
+			}
+			if m.screen == screenList && m.reviewerFilter != nil {
+				m.reviewerFilter = nil
+				m = m.applyFilter()
+				return m, nil
+			}
 			if m.screen == screenDetail {
 				m.screen = screenList
+				var changed bool
+				if m, changed = m.leaveDetail(time.Now()); changed {
+					return m, m.saveStateCmd()
+				}
 				return m, nil
 			}
 		case "tab":
 			if m.screen == screenDetail {
 				if m.detailSubTab == subTabDetail {
 					m.detailSubTab = subTabDiff
-				} else {
-					m.detailSubTab = subTabDetail
+					return m, m.diffTab.PageCmd()
 				}
+				m.detailSubTab = subTabDetail
 				return m, nil
 			}
 		case "f":
 			if m.screen == screenList {
-				m.filter = m.filter.Next()
+				m.reviewerFilter = nil
+				m.filter = m.filter.Next(len(m.customFilters))
+				m = m.applyFilter()
+				return m, nil
+			}
+		case "s":
+			if m.screen == screenDetail && m.detailSubTab == subTabDiff {
+				m.diffSplit = !m.diffSplit
+				m = m.refreshDiff()
+				return m, nil
+			}
+			if m.screen == screenList {
+				m.sortMode = m.sortMode.Next()
+				m = m.applyFilter().withSortSaved()
+				return m, m.saveStateCmd()
+			}
+		case "S":
+			if m.screen == screenList {
+				m.sortReverse = !m.sortReverse
+				m = m.applyFilter().withSortSaved()
+				return m, m.saveStateCmd()
+			}
+		case "z":
+			if m.screen == screenList && m.prsTab.SelectedPR() != nil {
+				m.snoozeMenu = true
+				return m, nil
+			}
+		case "Z":
+			if m.screen == screenList {
+				m.showSnoozed = !m.showSnoozed
 				m = m.applyFilter()
 				return m, nil
 			}
+		case "t":
+			if m.screen == screenList {
+				m.screen = screenStats
+				return m.loadStats()
+			}
+		case "v":
+			if m.screen == screenDetail && m.detailSubTab == subTabDiff {
+				return m.showFile(viewHead)
+			}
+			if m.screen == screenList {
+				m.screen = screenReviewers
+				m.loadingReviewers = true
+				return m, m.reviewsFetchCmd()
+			}
+		case "W":
+			if m.screen == screenList {
+				m.screen = screenWorktrees
+				m.worktreesTab.status = ""
+				m.loadingWorktrees = true
+				return m, m.worktreesLoadCmd()
+			}
+		case " ":
+			if m.screen == screenWorktrees {
+				m.worktreesTab = m.worktreesTab.ToggleMark()
+				return m, nil
+			}
+		case "L":
+			if m.screen == screenList {
+				m.screen = screenLog
+				return m, nil
+			}
+		case "c":
+			if m.screen == screenLog {
+				m.logPanel = m.logPanel.Clear()
+				return m, nil
+			}
+			if m.screen == screenRun {
+				m.runTab.output = m.runTab.output.Clear()
+				return m, nil
+			}
+		case "R":
+			if m.screen == screenList || m.screen == screenDetail {
+				m = m.openRun()
+				return m, nil
+			}
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			if m.screen == screenRun {
+				if p, ok := m.runTab.Preset(msg.String()); ok {
+					return m.startRun(p.name, p.script)
+				}
+				return m, nil
+			}
+		case "!":
+			if m.screen == screenRun {
+				m.runTab.editing = true
+				m.runTab.input = newCommandInput()
+				return m, m.runTab.input.Focus()
+			}
+		case "x":
+			if m.screen == screenRun {
+				m.runTab.Cancel()
+				return m, nil
+			}
+			if m.screen == screenTests {
+				m.testsTab.Cancel()
+				return m, nil
+			}
+			if pr := m.hooksTarget(); pr != nil {
+				if !m.cancelHooks(pr.Number) {
+					m.notice = fmt.Sprintf("#%d has no hooks running", pr.Number)
+				}
+				return m, nil
+			}
+		case "T":
+			if m.screen == screenDetail {
+				return m.startGoTests()
+			}
+		case "C":
+			if m.screen == screenDetail {
+				return m.toggleCoverage()
+			}
+		case "e", "+", "-", "F":
+			if m.screen == screenDetail && m.detailSubTab == subTabDiff {
+				return m.updateDiffOptions(msg.String())
+			}
+		case "|":
+			if m.screen == screenDetail && m.detailSubTab == subTabDiff {
+				return m.togglePager()
+			}
+		case "n", "N":
+			if m.screen == screenDetail && m.detailSubTab == subTabDiff {
+				delta := 1
+				if msg.String() == "N" {
+					delta = -1
+				}
+				m.diffTab = m.diffTab.JumpHunk(delta)
+				return m, m.diffTab.PageCmd()
+			}
+		case "]", "[":
+			if m.screen == screenDetail && m.detailSubTab == subTabDiff {
+				delta := 1
+				if msg.String() == "[" {
+					delta = -1
+				}
+				m.diffTab = m.diffTab.JumpFile(delta)
+				return m, m.diffTab.PageCmd()
+			}
+		case "V":
+			if m.screen == screenDetail && m.detailSubTab == subTabDiff {
+				return m.showFile(viewBase)
+			}
+		case "<", ">":
+			if m.screen == screenDetail && m.detailSubTab == subTabDiff {
+				dir := 1
+				if msg.String() == "<" {
+					dir = -1
+				}
+				return m.expandHunk(dir)
+			}
+		case ":":
+			if m.screen == screenDetail && m.detailSubTab == subTabDiff {
+				m.lineInputActive = true
+				m.lineInput = newLineInput()
+				return m, m.lineInput.Focus()
+			}
+		case "d":
+			if m.screen == screenDetail && m.selectedPR != nil {
+				return m, m.difftoolCmd(*m.selectedPR)
+			}
+		case "M":
+			if m.screen == screenWorktrees {
+				return m, m.migrateWorktreesCmd(m.worktreesTab.Misplaced())
+			}
+		case "P":
+			if m.screen == screenWorktrees {
+				return m, m.inspectRemovalCmd(removeTargets(m.worktreesTab.Closed()), true)
+			}
+		case "p":
+			if m.screen == screenStats {
+				m.statsPeriod = (m.statsPeriod + 1) % len(statsPeriods)
+				return m.loadStats()
+			}
 		case "r":
+			if m.screen == screenStats {
+				return m.loadStats()
+			}
+			if m.screen == screenReviewers {
+				m.loadingReviewers = true
+				return m, m.reviewsFetchCmd()
+			}
+			if m.screen == screenWorktrees {
+				m.loadingWorktrees = true
+				return m, m.worktreesLoadCmd()
+			}
+			if m.screen == screenTests {
+				return m.startGoTests()
+			}
 			m.loading = true
 			return m, m.fetchCmd()
 		case "enter":
+			if m.screen == screenReviewers {
+				if r := m.reviewersTab.Selected(); r != nil {
+					r := *r
+					m.reviewerFilter = &r
+					m.screen = screenList
+					m = m.applyFilter()
+				}
+				return m, nil
+			}
 			if m.screen == screenList {
 				if pr := m.prsTab.SelectedPR(); pr != nil {
 					pr := *pr
+					if m.selectedPR == nil || m.selectedPR.Number != pr.Number {
+						m.diffTab = m.diffTab.ForgetFiles()
+					}
 					m.selectedPR = &pr
 					m.screen = screenDetail
+					m.detailOpenedAt = time.Now()
 					m.detailSubTab = subTabDetail
 					m.detailTab = m.detailTab.SetPR(m.selectedPR)
-					m.diffTab = m.diffTab.SetFiles(m.selectedPR.DiffFiles)
+					m.diffTab.fileList.Select(0)
+					m = m.refreshDiff()
+					var cmds []tea.Cmd
+					if m.diffLocal {
+						cmds = append(cmds, m.localDiffCmd(pr, false, true))
+					}
 					if !pr.DetailLoaded {
 						m.loadingDetail = true
-						return m, m.detailFetchCmd(pr)
+						cmds = append(cmds, m.detailFetchCmd(pr))
 					}
-					return m, nil
+					return m, tea.Batch(cmds...)
 				}
 			}
 		case "w":
 			if m.screen == screenList {
 				return m, m.worktreeCmd()
 			}
+			if m.screen == screenDetail && m.detailSubTab == subTabDiff {
+				return m.updateDiffOptions(msg.String())
+			}
 		case "o":
 			if m.screen == screenList {
 				if pr := m.prsTab.SelectedPR(); pr != nil && pr.HasWorktree {
 					return m, openEditorCmd(pr.WorktreePath)
 				}
 			}
+			if m.screen == screenWorktrees {
+				if r := m.worktreesTab.Selected(); r != nil {
+					return m, openEditorCmd(r.Path)
+				}
+			}
+		case "B":
+			if m.screen == screenList {
+				return m, m.trackingWorktreeCmd()
+			}
+		case "U":
+			if m.screen == screenList || m.screen == screenDetail {
+				return m, m.updateWorktreeCmd()
+			}
 		case "D":
 			if m.screen == screenList {
-				return m, m.removeWorktreeCmd()
+				if pr := m.prsTab.SelectedPR(); pr != nil && pr.HasWorktree {
+					return m, m.inspectRemovalCmd([]removeTarget{{prNumber: pr.Number, path: pr.WorktreePath}}, false)
+				}
+			}
+			if m.screen == screenWorktrees {
+				return m, m.inspectRemovalCmd(removeTargets(m.worktreesTab.Targets()), false)
+			}
+		case "u":
+			if (m.screen == screenList || m.screen == screenWorktrees) && m.canUndoRemove(time.Now()) {
+				cmd := m.restoreWorktreesCmd()
+				m.lastRemoved = nil
+				return m, cmd
 			}
 		}
 	}
 
 	var cmd tea.Cmd
-	if m.screen == screenList {
+	switch m.screen {
+	case screenList:
 		m.prsTab, cmd = m.prsTab.Update(msg)
-	} else {
+	case screenStats:
+		m.statsTab, cmd = m.statsTab.Update(msg)
+	case screenReviewers:
+		m.reviewersTab, cmd = m.reviewersTab.Update(msg)
+	case screenWorktrees:
+		m.worktreesTab, cmd = m.worktreesTab.Update(msg)
+	case screenLog:
+		m.logPanel, cmd = m.logPanel.Update(msg)
+	case screenRun:
+		m.runTab, cmd = m.runTab.Update(msg)
+	case screenTests:
+		m.testsTab, cmd = m.testsTab.Update(msg)
+	default:
 		switch m.detailSubTab {
 		case subTabDetail:
 			m.detailTab, cmd = m.detailTab.Update(msg)
@@ -343,42 +1080,284 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-// applyFilter filters allPRs client-side and updates the PRs tab.
+// applyFilter filters and sorts allPRs client-side and updates the PRs tab.
 func (m AppModel) applyFilter() AppModel {
-	m.prs = model.FilterPRs(m.allPRs, m.filter, m.currentUser)
+	var filtered []model.PR
+	if r := m.reviewerFilter; r != nil {
+		for _, pr := range m.allPRs {
+			if m.withReviewers(pr).InvolvesReviewer(r.Name, r.IsTeam) {
+				filtered = append(filtered, pr)
+			}
+		}
+	} else if i, ok := m.filter.CustomIndex(); ok && i < len(m.customFilters) {
+		filtered = model.FilterCustomPRs(m.allPRs, m.customFilters[i], m.currentUser, time.Now())
+	} else {
+		filtered = model.FilterPRs(m.allPRs, m.filter, m.currentUser)
+	}
+	now := time.Now()
+	m.overdueCount = 0
+	for _, pr := range m.allPRs {
+		if pr = m.annotate(pr, now); pr.SLA == model.SLAOverdue && pr.Snooze == nil {
+			m.overdueCount++
+		}
+	}
+	visible := make([]model.PR, 0, len(filtered))
+	m.snoozedCount = 0
+	for _, pr := range filtered {
+		pr = m.annotate(pr, now)
+		if pr.Snooze != nil {
+			m.snoozedCount++
+			if !m.showSnoozed {
+				continue
+			}
+		}
+		visible = append(visible, pr)
+	}
+	m.prs = model.SortPRs(visible, m.sortMode, m.sortReverse)
 	m.prsTab = m.prsTab.SetPRs(m.prs)
 	return m
 }
 
+// annotate fills in the locally derived fields of pr: its active snooze and
+// its priority score.
+func (m AppModel) annotate(pr model.PR, now time.Time) model.PR {
+	pr.Snooze = nil
+	if s, ok := m.state.Snoozes[model.SnoozeKey(m.repoName, pr.Number)]; ok && !s.Expired(pr, false, now) {
+		pr.Snooze = &s
+	}
+	pr.Priority = model.Priority(pr, m.weights, now)
+	pr.SLA = m.sla.Level(pr, now)
+	pr.Waiting = m.sla.Waiting(pr, now)
+	pr.Hooks = m.hookStatus[pr.Number]
+	pr.LocalRun = m.localRun(pr)
+	pr.ReviewEstimate = 0
+	if pr.FilesLoaded {
+		if est, ok := model.EstimateReviewTime(m.state.ReviewHistory, pr.ReviewableLines()); ok {
+			pr.ReviewEstimate = est
+		}
+	}
+	return pr
+}
+
+// filterLabel returns the display name of the active filter.
+func (m AppModel) filterLabel() string {
+	if m.reviewerFilter != nil {
+		return "Reviewer " + m.reviewerFilter.Label()
+	}
+	if i, ok := m.filter.CustomIndex(); ok && i < len(m.customFilters) {
+		return m.customFilters[i].Name
+	}
+	return m.filter.Label()
+}
+
+// withSortSaved copies the current sort mode and direction into the
+// persisted state.
+func (m AppModel) withSortSaved() AppModel {
+	m.state.Sort = m.sortMode.Key()
+	m.state.SortReverse = m.sortReverse
+	return m
+}
+
+func (m AppModel) saveStateCmd() tea.Cmd {
+	if m.store == nil {
+		return nil
+	}
+	store, st := m.store, m.state.Clone()
+	if !m.lastSync.IsZero() && m.err == nil {
+		m.prunePending(st.ReviewPending)
+	}
+	return func() tea.Msg {
+		return stateSavedMsg{err: store.Save(st)}
+	}
+}
+
+// withFiles carries the files loaded for prev over to the refetched prs whose
+// head has not moved, so sizes stay known between refreshes. CI, reviews and
+// comments are fetched again.
+func withFiles(prs, prev []model.PR) []model.PR {
+	loaded := make(map[int]model.PR)
+	for _, pr := range prev {
+		if pr.FilesLoaded {
+			loaded[pr.Number] = pr
+		}
+	}
+	for i := range prs {
+		if old, ok := loaded[prs[i].Number]; ok && old.HeadSHA == prs[i].HeadSHA {
+			prs[i].DiffFiles = old.DiffFiles
+			prs[i].FilesLoaded = true
+		}
+	}
+	return prs
+}
+
+// withWorktrees maps worktree records onto the PRs they belong to. The push
+// state of branch worktrees is looked up with status.
+func withWorktrees(prs []model.PR, wts map[int]git.Worktree, status func(path string) *model.TrackingStatus) []model.PR {
+	for i := range prs {
+		wt, ok := wts[prs[i].Number]
+		if !ok {
+			continue
+		}
+		prs[i].HasWorktree = true
+		prs[i].WorktreePath = wt.Path
+		prs[i].WorktreeHEAD = wt.HEAD
+		if wt.Branch != "" {
+			prs[i].WorktreeBranch = status(wt.Path)
+		}
+	}
+	return prs
+}
+
+// branchStatus returns the push state of the branch worktree at path.
+func branchStatus(path string) *model.TrackingStatus {
+	st, ok, err := git.WorktreeBranchStatus(path)
+	if !ok || err != nil {
+		return nil
+	}
+	return &model.TrackingStatus{Branch: st.Branch, Upstream: st.Upstream, Ahead: st.Ahead, Behind: st.Behind}
+}
+
+// worktreePath returns where the configured layout places pr's worktree.
+func (m AppModel) worktreePath(pr model.PR) string {
+	return m.wtLayout.Path(m.repoRoot, git.LayoutVars{
+		Owner:   m.repoOwner,
+		Repo:    m.repoRepo,
+		Number:  pr.Number,
+		HeadRef: pr.HeadRef,
+		Author:  pr.Author,
+	})
+}
+
 func (m AppModel) worktreeCmd() tea.Cmd {
 	pr := m.prsTab.SelectedPR()
 	if pr == nil {
 		return nil
 	}
 	prNum := pr.Number
+	path := m.worktreePath(*pr)
+	repoRoot, layout := m.repoRoot, m.wtLayout
+	return func() tea.Msg {
+		if git.WorktreeExists(repoRoot, layout, prNum) {
+			return tickMsg(time.Now())
+		}
+		if err := git.CreateWorktree(repoRoot, path, prNum); err != nil {
+			return fetchedMsg{err: fmt.Errorf("worktree: %w", err)}
+		}
+		return worktreeCreatedMsg{prNumber: prNum, path: path}
+	}
+}
+
+// trackingWorktreeCmd creates the selected PR's worktree on a local branch
+// tracking the PR head, so fixups can be pushed to the PR.
+func (m AppModel) trackingWorktreeCmd() tea.Cmd {
+	pr := m.prsTab.SelectedPR()
+	if pr == nil || pr.HasWorktree {
+		return nil
+	}
+	if pr.HeadRepo == "" {
+		return func() tea.Msg {
+			return fetchedMsg{err: fmt.Errorf("worktree: the head repository of #%d was deleted", pr.Number)}
+		}
+	}
+	src := git.TrackingSource{
+		PRNumber: pr.Number,
+		Path:     m.worktreePath(*pr),
+		Branch:   pr.HeadRef,
+		Fork:     pr.IsFork(m.repoName),
+		CloneURL: pr.HeadCloneURL,
+		SSHURL:   pr.HeadSSHURL,
+		Editable: pr.HeadEditable,
+	}
+	if owner, _, ok := strings.Cut(pr.HeadRepo, "/"); ok {
+		src.Owner = owner
+	}
 	repoRoot := m.repoRoot
 	return func() tea.Msg {
-		if !git.WorktreeExists(repoRoot, prNum) {
-			if err := git.CreateWorktree(repoRoot, prNum); err != nil {
-				return fetchedMsg{err: fmt.Errorf("worktree: %w", err)}
+		if err := git.CreateTrackingWorktree(repoRoot, src); err != nil {
+			return fetchedMsg{err: fmt.Errorf("worktree: %w", err)}
+		}
+		return worktreeCreatedMsg{prNumber: src.PRNumber, path: src.Path}
+	}
+}
+
+// migrateWorktreesCmd moves worktrees to where the configured layout places
+// them.
+func (m AppModel) migrateWorktreesCmd(rows []worktreeRow) tea.Cmd {
+	if len(rows) == 0 {
+		return nil
+	}
+	repoRoot := m.repoRoot
+	return func() tea.Msg {
+		var msg worktreesMigratedMsg
+		var errs []error
+		for _, r := range rows {
+			if err := git.MoveWorktree(repoRoot, r.Path, r.Target); err != nil {
+				errs = append(errs, err)
+				continue
 			}
+			msg.moved++
 		}
-		return tickMsg(time.Now())
+		msg.err = errors.Join(errs...)
+		return msg
 	}
 }
 
-func (m AppModel) removeWorktreeCmd() tea.Cmd {
+// updateWorktreeCmd moves the worktree of the selected PR, or of the PR shown
+// in the detail screen, to the PR's latest head.
+func (m AppModel) updateWorktreeCmd() tea.Cmd {
 	pr := m.prsTab.SelectedPR()
+	if m.screen == screenDetail {
+		pr = m.selectedPR
+	}
 	if pr == nil || !pr.HasWorktree {
 		return nil
 	}
-	prNum := pr.Number
+	prNum, path := pr.Number, pr.WorktreePath
 	repoRoot := m.repoRoot
 	return func() tea.Msg {
-		if err := git.RemoveWorktree(repoRoot, prNum); err != nil {
-			return fetchedMsg{err: fmt.Errorf("remove worktree: %w", err)}
+		u, err := git.UpdateWorktree(repoRoot, path, prNum)
+		return worktreeUpdatedMsg{prNumber: prNum, path: path, update: u, err: err}
+	}
+}
+
+// worktreesLoadCmd lists the worktrees under .worktrees. PRs in the open
+// list are known to be open; the state of any other PR is fetched.
+func (m AppModel) worktreesLoadCmd() tea.Cmd {
+	open := make(map[int]model.PR, len(m.allPRs))
+	for _, pr := range m.allPRs {
+		open[pr.Number] = pr
+	}
+	repoRoot, layout := m.repoRoot, m.wtLayout
+	// Without the PR, the target path is only known when the layout does not
+	// depend on the PR's branch or author.
+	needsPR := strings.Contains(layout.Name, "{headRef}") || strings.Contains(layout.Name, "{author}")
+	return func() tea.Msg {
+		wts, err := git.ManagedWorktrees(repoRoot, layout)
+		if err != nil {
+			return worktreesLoadedMsg{err: err}
+		}
+		rows := make([]worktreeRow, len(wts))
+		eg, ctx := errgroup.WithContext(context.Background())
+		eg.SetLimit(4)
+		for i, wt := range wts {
+			rows[i] = worktreeRow{WorktreeStatus: wt}
+			if pr, ok := open[wt.PRNumber]; ok {
+				rows[i].PRState, rows[i].Title = "open", pr.Title
+				rows[i].Outdated = pr.HeadSHA != "" && wt.HEAD != pr.HeadSHA
+				rows[i].Target = m.worktreePath(pr)
+				continue
+			}
+			if !needsPR {
+				rows[i].Target = m.worktreePath(model.PR{Number: wt.PRNumber})
+			}
+			eg.Go(func() error {
+				// Best effort: an unknown state only keeps the row out of prune.
+				rows[i].PRState, _ = github.FetchPRState(ctx, m.ghClient, m.repoOwner, m.repoRepo, wt.PRNumber)
+				return nil
+			})
 		}
-		return tickMsg(time.Now())
+		_ = eg.Wait()
+		return worktreesLoadedMsg{rows: rows}
 	}
 }
 
@@ -401,10 +1380,49 @@ func (m AppModel) View() string {
 
 func (m AppModel) buildTopBorder() string {
 	var inner string
-	if m.screen == screenList {
+	if m.screen == screenReviewers {
+		inner = "─" + fmt.Sprintf("[gh-review — %s — Reviewers]", m.repoName)
+	} else if m.screen == screenLog {
+		inner = "─" + fmt.Sprintf("[gh-review — %s — Log]", m.repoName)
+	} else if m.screen == screenRun {
+		inner = "─" + fmt.Sprintf("[gh-review — %s — Run #%d]", m.repoName, m.runTab.prNumber)
+		if m.runTab.running != "" {
+			inner += "─" + styleCIPending.Render("running "+m.runTab.running)
+		}
+	} else if m.screen == screenTests {
+		inner = "─" + fmt.Sprintf("[gh-review — %s — Tests #%d]", m.repoName, m.testsTab.prNumber)
+		if s := m.testsTab.summary(); s != "" {
+			inner += "─" + s
+		}
+	} else if m.screen == screenWorktrees {
+		inner = "─" + fmt.Sprintf("[gh-review — %s — Worktrees (%d)]", m.repoName, len(m.worktreesTab.rows))
+		if closed := len(m.worktreesTab.Closed()); closed > 0 {
+			inner += "─" + lipgloss.NewStyle().Foreground(colorYellow).Render(fmt.Sprintf("[P] %d closed", closed))
+		}
+		if n := len(m.worktreesTab.Misplaced()); n > 0 {
+			inner += "─" + lipgloss.NewStyle().Foreground(colorCyan).Render(fmt.Sprintf("[M] %d to migrate", n))
+		}
+		if m.worktreesTab.status != "" {
+			inner += "─" + lipgloss.NewStyle().Foreground(colorGray).Render(m.worktreesTab.status)
+		}
+	} else if m.screen == screenStats {
+		title := fmt.Sprintf("[gh-review — %s — Stats]", m.repoName)
+		period := lipgloss.NewStyle().Foreground(colorYellow).Render(fmt.Sprintf("[p] last %d days", statsPeriods[m.statsPeriod]))
+		inner = "─" + title + "─" + period
+	} else if m.screen == screenList {
 		title := fmt.Sprintf("[gh-review — %s]", m.repoName)
-		filter := lipgloss.NewStyle().Foreground(colorYellow).Render("[f] " + m.filter.Label())
-		inner = "─" + title + "─" + filter
+		filter := lipgloss.NewStyle().Foreground(colorYellow).Render(fmt.Sprintf("[f] %s (%d)", m.filterLabel(), len(m.prs)))
+		inner = "─" + title + "─" + filter + "─" + m.sortLabel()
+		if m.overdueCount > 0 {
+			inner += "─" + styleSLAOverdue.Render(fmt.Sprintf("%d overdue", m.overdueCount))
+		}
+		if m.snoozedCount > 0 {
+			verb := "hidden"
+			if m.showSnoozed {
+				verb = "shown"
+			}
+			inner += "─" + lipgloss.NewStyle().Foreground(colorGray).Render(fmt.Sprintf("[Z] %d snoozed %s", m.snoozedCount, verb))
+		}
 	} else {
 		prTitle := ""
 		if m.selectedPR != nil {
@@ -413,6 +1431,9 @@ func (m AppModel) buildTopBorder() string {
 		title := fmt.Sprintf("[gh-review — %s%s]", m.repoName, prTitle)
 		subTabs := m.renderSubTabsStr()
 		inner = "─" + title + "─" + subTabs
+		if pos := m.diffTab.Position(); m.detailSubTab == subTabDiff && pos != "" {
+			inner += "─" + lipgloss.NewStyle().Foreground(colorGray).Render(pos)
+		}
 	}
 	innerW := lipgloss.Width(inner)
 	pad := m.width - 2 - innerW
@@ -436,6 +1457,14 @@ func (m AppModel) buildBottomBorder() string {
 	return lipgloss.NewStyle().Foreground(colorGreen).Render(line)
 }
 
+func (m AppModel) sortLabel() string {
+	dir := "↓"
+	if m.sortReverse {
+		dir = "↑"
+	}
+	return lipgloss.NewStyle().Foreground(colorCyan).Render("[s] " + m.sortMode.Label() + " " + dir)
+}
+
 func (m AppModel) renderSubTabsStr() string {
 	var detail, diff string
 	if m.detailSubTab == subTabDetail {
@@ -449,14 +1478,50 @@ func (m AppModel) renderSubTabsStr() string {
 }
 
 func (m AppModel) helpStr() string {
+	if m.dateInputActive {
+		return m.dateInput.View()
+	}
+	if m.lineInputActive {
+		return m.lineInput.View()
+	}
+	if m.removeConfirm != nil {
+		return m.removeConfirm.prompt()
+	}
+	if m.helpOpen {
+		return "[any key]close"
+	}
+	if m.snoozeMenu {
+		return "snooze: [1/3/7]days [d]ate [c]ommits [m]ention [x]mute [u]nsnooze [Esc]cancel"
+	}
+	if m.screen == screenReviewers {
+		return "[Enter]show PRs [j/k]move [r]efresh [Esc/b]back [?]help [q]quit"
+	}
+	if m.screen == screenLog {
+		return "[j/k]scroll [c]lear [Esc/b]back [?]help [q]quit"
+	}
+	if m.screen == screenRun {
+		if m.runTab.editing {
+			return "[Enter]run [Esc]cancel"
+		}
+		return "[1-9]preset [!]command [x]cancel [j/k]scroll [c]lear [Esc/b]back [?]help [q]quit"
+	}
+	if m.screen == screenTests {
+		return "[x]cancel [r]erun [j/k]scroll [Esc/b]back [?]help [q]quit"
+	}
+	if m.screen == screenWorktrees {
+		return "[Space]mark [D]delete [P]rune closed [M]igrate [o]open [r]efresh [Esc/b]back [?]help [q]quit"
+	}
+	if m.screen == screenStats {
+		return "[p]eriod [j/k]scroll [r]efresh [Esc/b]back [?]help [q]quit"
+	}
 	if m.screen == screenList {
-		return "[Enter]detail [w]worktree [o]open [D]delete [f]filter [r]efresh [q]quit"
+		return "[Enter]detail [w]worktree [f]filter [s/S]sort [z]snooze [R]un [r]efresh [?]help [q]quit"
 	}
 	switch m.detailSubTab {
 	case subTabDiff:
-		return "[tab]switch [enter]focus [j/k]scroll [Esc/b]back [q]quit"
+		return "[tab]switch [n/N]hunk [[/]]file [:]line [v/V]whole file [Esc/b]back [?]help [q]quit"
 	default:
-		return "[tab]switch [j/k]scroll [Esc/b]back [q]quit"
+		return "[tab]switch [R]un [T]est [d]ifftool [x]cancel hooks [Esc/b]back [?]help [q]quit"
 	}
 }
 
@@ -464,6 +1529,9 @@ func (m AppModel) syncStr() string {
 	if m.err != nil {
 		return lipgloss.NewStyle().Foreground(colorRed).Render("Error: " + m.err.Error())
 	}
+	if m.notice != "" {
+		return lipgloss.NewStyle().Foreground(colorYellow).Render(m.notice)
+	}
 	if m.loading {
 		return "Syncing..."
 	}
@@ -475,6 +1543,9 @@ func (m AppModel) syncStr() string {
 }
 
 func (m AppModel) renderBody() string {
+	if m.helpOpen {
+		return m.renderHelp()
+	}
 	if m.loading && len(m.allPRs) == 0 {
 		return lipgloss.NewStyle().
 			Width(m.width - 2).
@@ -485,6 +1556,45 @@ func (m AppModel) renderBody() string {
 	if m.screen == screenList {
 		return m.prsTab.View()
 	}
+	if m.screen == screenReviewers {
+		if m.loadingReviewers {
+			return lipgloss.NewStyle().
+				Width(m.width-2).
+				Height(m.height-4).
+				Align(lipgloss.Center, lipgloss.Center).
+				Render(m.spinner.View() + " Loading reviewers...")
+		}
+		return m.reviewersTab.View()
+	}
+	if m.screen == screenLog {
+		return m.logPanel.View()
+	}
+	if m.screen == screenRun {
+		return m.runTab.View()
+	}
+	if m.screen == screenTests {
+		return m.testsTab.View()
+	}
+	if m.screen == screenWorktrees {
+		if m.loadingWorktrees {
+			return lipgloss.NewStyle().
+				Width(m.width-2).
+				Height(m.height-4).
+				Align(lipgloss.Center, lipgloss.Center).
+				Render(m.spinner.View() + " Loading worktrees...")
+		}
+		return m.worktreesTab.View()
+	}
+	if m.screen == screenStats {
+		if m.loadingStats {
+			return lipgloss.NewStyle().
+				Width(m.width - 2).
+				Height(m.height - 4).
+				Align(lipgloss.Center, lipgloss.Center).
+				Render(m.spinner.View() + " Loading stats...")
+		}
+		return m.statsTab.View()
+	}
 	// screenDetail
 	if m.loadingDetail {
 		return lipgloss.NewStyle().
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kosuke9809/gh-review/coverage"
+)
+
+// coverageLoadedMsg carries the coverage profile read from a PR's worktree.
+type coverageLoadedMsg struct {
+	prNumber int
+	path     string
+	profile  *coverage.Profile
+	err      error
+}
+
+// coverageGutter marks an added line as covered or not.
+func coverageGutter(m coverage.Mark) string {
+	switch m {
+	case coverage.MarkCovered:
+		return styleCIPass.Render("▌")
+	case coverage.MarkUncovered:
+		return styleCIFail.Render("▌")
+	}
+	return " "
+}
+
+// coveragePercent renders a patch coverage, or "" when no added line is
+// instrumented.
+func coveragePercent(s coverage.Summary) string {
+	pct, ok := s.Percent()
+	if !ok {
+		return ""
+	}
+	style := styleSizeMid
+	switch {
+	case pct >= 80:
+		style = styleCIPass
+	case pct < 50:
+		style = styleCIFail
+	}
+	return style.Render(fmt.Sprintf("%.0f%%", pct))
+}
+
+// toggleCoverage loads the coverage profile from the worktree of the PR shown
+// in the detail screen, or removes the overlay when it is shown.
+func (m AppModel) toggleCoverage() (AppModel, tea.Cmd) {
+	pr := m.selectedPR
+	if pr == nil {
+		return m, nil
+	}
+	if _, ok := m.profiles[pr.Number]; ok {
+		delete(m.profiles, pr.Number)
+		m.diffTab = m.diffTab.SetCoverage(nil)
+		return m, nil
+	}
+	if !pr.HasWorktree {
+		m.notice = fmt.Sprintf("#%d has no worktree  [w]orktree", pr.Number)
+		return m, nil
+	}
+	prNum, dir := pr.Number, pr.WorktreePath
+	names := coverage.DefaultFiles
+	if m.coverFile != "" {
+		names = []string{m.coverFile}
+	}
+	return m, func() tea.Msg {
+		path, err := coverage.Find(dir, names)
+		if err != nil {
+			return coverageLoadedMsg{prNumber: prNum, err: err}
+		}
+		p, err := coverage.ParseFile(path)
+		return coverageLoadedMsg{prNumber: prNum, path: path, profile: p, err: err}
+	}
+}
+
+// applyCoverage shows a loaded profile on the diff of its PR.
+func (m AppModel) applyCoverage(msg coverageLoadedMsg) AppModel {
+	if msg.err != nil {
+		m.notice = fmt.Sprintf("#%d coverage: %v", msg.prNumber, msg.err)
+		return m
+	}
+	m.profiles[msg.prNumber] = msg.profile
+	if m.selectedPR == nil || m.selectedPR.Number != msg.prNumber {
+		return m
+	}
+	m.diffTab = m.diffTab.SetCoverage(msg.profile)
+	m.notice = fmt.Sprintf("coverage from %s", filepath.Base(msg.path))
+	if s, _ := m.diffTab.CoverageSummary(); s.Covered+s.Uncovered > 0 {
+		pct, _ := s.Percent()
+		m.notice += fmt.Sprintf(": %.0f%% of added lines (%d/%d)", pct, s.Covered, s.Covered+s.Uncovered)
+	} else {
+		m.notice += ": no added lines instrumented"
+	}
+	return m
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kosuke9809/gh-review/coverage"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestDiffTab_SetCoverage(t *testing.T) {
+	files := []model.DiffFile{
+		{Filename: "a/a.go", Additions: 2, Patch: "@@ -1,1 +1,3 @@\n ctx\n+covered\n+uncovered"},
+		{Filename: "README.md", Additions: 1, Patch: "@@ -1,0 +1,1 @@\n+docs"},
+	}
+	p := &coverage.Profile{Files: map[string]map[int]bool{"example.com/m/a/a.go": {2: true, 3: false}}}
+	tab := newDiffTab(120, 40).SetFiles(files).SetCoverage(p)
+
+	s, ok := tab.CoverageSummary()
+	if !ok || s.Covered != 1 || s.Uncovered != 1 {
+		t.Errorf("CoverageSummary() = %+v, %v; want 1 covered, 1 uncovered", s, ok)
+	}
+	items := tab.fileItems()
+	if got := items[0].(fileItem).coverage; !strings.Contains(got, "50%") {
+		t.Errorf("a/a.go coverage = %q, want 50%%", got)
+	}
+	if got := items[1].(fileItem).coverage; got != "" {
+		t.Errorf("README.md coverage = %q, want none", got)
+	}
+	if !strings.Contains(tab.renderPatch(files[0]), "▌") {
+		t.Error("patch has no coverage gutter")
+	}
+
+	tab = tab.SetCoverage(nil)
+	if _, ok := tab.CoverageSummary(); ok || strings.Contains(tab.renderPatch(files[0]), "▌") {
+		t.Error("coverage still shown after it was removed")
+	}
+}
+
+func TestApplyCoverage(t *testing.T) {
+	pr := model.PR{Number: 4, DiffFiles: []model.DiffFile{{Filename: "a.go", Patch: "@@ -0,0 +1,2 @@\n+x\n+y"}}}
+	m := AppModel{selectedPR: &pr, profiles: make(map[int]*coverage.Profile), diffTab: newDiffTab(120, 40).SetFiles(pr.DiffFiles)}
+	p := &coverage.Profile{Files: map[string]map[int]bool{"m/a.go": {1: true, 2: true}}}
+	m = m.applyCoverage(coverageLoadedMsg{prNumber: 4, path: "/wt/cover.out", profile: p})
+	if m.profiles[4] != p {
+		t.Error("profile not kept for the PR")
+	}
+	if want := "coverage from cover.out: 100% of added lines (2/2)"; m.notice != want {
+		t.Errorf("notice = %q, want %q", m.notice, want)
+	}
+}
@@ -8,31 +8,34 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/kosuke9809/gh-review/git"
 	"github.com/kosuke9809/gh-review/model"
 )
 
 type detailTabModel struct {
 	viewport viewport.Model
 	pr       *model.PR
+	sched    model.WorkSchedule // to show waiting time in working days
 	width    int
 	height   int
 }
 
-func newDetailTab(width, height int) detailTabModel {
+func newDetailTab(width, height int, sched model.WorkSchedule) detailTabModel {
 	vp := viewport.New(width, height-4)
-	return detailTabModel{viewport: vp, width: width, height: height}
+	return detailTabModel{viewport: vp, sched: sched, width: width, height: height}
 }
 
 func (m detailTabModel) SetPR(pr *model.PR) detailTabModel {
 	m.pr = pr
 	if pr != nil {
-		m.viewport.SetContent(RenderDetailContent(*pr))
+		m.viewport.SetContent(RenderDetailContent(*pr, m.sched))
 	}
 	return m
 }
 
-// RenderDetailContent builds the text content for the Detail tab.
-func RenderDetailContent(pr model.PR) string {
+// RenderDetailContent builds the text content for the Detail tab, showing
+// waiting time in the working days of sched.
+func RenderDetailContent(pr model.PR, sched model.WorkSchedule) string {
 	var b strings.Builder
 	sep := lipgloss.NewStyle().Foreground(colorGray).Render(strings.Repeat("─", 60))
 
@@ -46,9 +49,43 @@ func RenderDetailContent(pr model.PR) string {
 		formatDuration(age),
 	))
 	b.WriteString(fmt.Sprintf("Branch: %s ← %s\n", pr.BaseRef, pr.HeadRef))
+	if pr.SLA != model.SLANone {
+		b.WriteString(fmt.Sprintf("Waiting on me: %s  (requested %s, working time; 1d = %s)\n",
+			waitingStr(pr, sched.Day()), pr.WaitingSince().Format("2006-01-02 15:04"), formatDuration(sched.Day())))
+	}
+	if size := pr.Size(); size != "" {
+		est := ""
+		if pr.ReviewEstimate > 0 {
+			est = fmt.Sprintf("  |  Estimated review: %s", formatDuration(pr.ReviewEstimate.Round(time.Minute)))
+		}
+		b.WriteString(fmt.Sprintf("Size: %s (%d lines, excluding generated)%s\n", size, pr.ReviewableLines(), est))
+	}
 
 	if pr.HasWorktree {
-		b.WriteString(fmt.Sprintf("Worktree: %s  [o:open] [D:delete]\n", pr.WorktreePath))
+		b.WriteString(fmt.Sprintf("Worktree: %s  [o:open] [R:run] [D:delete]\n", pr.WorktreePath))
+		if pr.Hooks == model.HookFailed {
+			b.WriteString("  " + styleCIFail.Render("Hooks failed  [L:log]") + "\n")
+		}
+		if pr.LocalRun != nil {
+			b.WriteString("  " + localRunStr(pr) + "\n")
+		}
+		if t := pr.WorktreeBranch; t != nil {
+			b.WriteString("  " + trackingStr(*t) + "\n")
+		}
+		if pr.WorktreeOutdated() {
+			b.WriteString(styleUnread.Render(fmt.Sprintf("  Worktree at %s, PR head is %s  [U:update]",
+				git.ShortSHA(pr.WorktreeHEAD), git.ShortSHA(pr.HeadSHA))))
+			b.WriteString("\n")
+		}
+	}
+
+	if len(pr.Priority.Components) > 0 {
+		b.WriteString("\n" + sep + "\n")
+		b.WriteString(lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Priority %.1f", pr.Priority.Total)))
+		b.WriteString("\n")
+		for _, c := range pr.Priority.Components {
+			b.WriteString(fmt.Sprintf("  %+6.1f  %-10s %s\n", c.Points, c.Name, c.Detail))
+		}
 	}
 
 	if pr.Body != "" {
@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kosuke9809/gh-review/model"
+	"github.com/kosuke9809/gh-review/patch"
+)
+
+// patchRows maps each line of f's patch to its row in the diff viewport, or
+// -1 for lines the view leaves out. It returns nil when the rows are not
+// known, as with pager output and the full-file views.
+func (m diffTabModel) patchRows(f model.DiffFile) []int {
+	if m.view != viewDiff || m.showsPaged(f) {
+		return nil
+	}
+	n := strings.Count(f.Patch, "\n") + 1
+	rows := make([]int, n)
+	if !m.splitActive() {
+		for i := range rows {
+			rows[i] = i
+		}
+		return rows
+	}
+	for i := range rows {
+		rows[i] = -1
+	}
+	row := 0
+	for _, h := range patch.Parse(f.Patch) {
+		rows[h.Lines[0].Index] = row
+		row++
+		for _, r := range patch.Split(h) {
+			if r.Old != nil {
+				rows[r.Old.Index] = row
+			}
+			if r.New != nil {
+				rows[r.New.Index] = row
+			}
+			row++
+		}
+	}
+	return rows
+}
+
+// hunkRows returns the viewport row of each hunk header of f, given the rows
+// of its patch lines, or in the full-file views, of each run of changed
+// lines.
+func (m diffTabModel) hunkRows(f model.DiffFile, rows []int) []int {
+	if m.view != viewDiff {
+		return m.changedRows(f)
+	}
+	if rows == nil {
+		return nil
+	}
+	var out []int
+	for _, h := range patch.Parse(f.Patch) {
+		out = append(out, rows[h.Lines[0].Index])
+	}
+	return out
+}
+
+// selectedFile returns the index of the file shown, or -1 without files.
+func (m diffTabModel) selectedFile() int {
+	if idx := m.fileList.Index(); idx >= 0 && idx < len(m.files) {
+		return idx
+	}
+	return -1
+}
+
+// currentHunk returns the index of the hunk at the top of the viewport, or
+// -1 above the first hunk.
+func (m diffTabModel) currentHunk(hunks []int) int {
+	cur := -1
+	for i, row := range hunks {
+		if row <= m.diffView.YOffset {
+			cur = i
+		}
+	}
+	return cur
+}
+
+// JumpFile shows the file delta files away from the current one.
+func (m diffTabModel) JumpFile(delta int) diffTabModel {
+	idx := m.selectedFile()
+	if idx < 0 {
+		return m
+	}
+	next := min(max(idx+delta, 0), len(m.files)-1)
+	if next != idx {
+		m.fileList.Select(next)
+		m = m.updateDiffView()
+	}
+	return m
+}
+
+// JumpHunk scrolls to the next hunk, or the previous one when delta is
+// negative, moving on to the neighbouring file past the first or last hunk.
+func (m diffTabModel) JumpHunk(delta int) diffTabModel {
+	idx := m.selectedFile()
+	if idx < 0 {
+		return m
+	}
+	hunks := m.hunks
+	cur := m.currentHunk(hunks)
+	target := cur + 1
+	if delta < 0 {
+		target = cur - 1
+		if cur >= 0 && hunks[cur] < m.diffView.YOffset {
+			// Inside a hunk, go back to its header first.
+			target = cur
+		}
+	}
+	if target >= 0 && target < len(hunks) {
+		m.diffView.SetYOffset(hunks[target])
+		return m
+	}
+	next := idx + 1
+	if delta < 0 {
+		next = idx - 1
+	}
+	if next < 0 || next >= len(m.files) {
+		return m
+	}
+	m = m.JumpFile(next - idx)
+	if delta < 0 && len(m.hunks) > 0 {
+		m.diffView.SetYOffset(m.hunks[len(m.hunks)-1])
+	}
+	return m
+}
+
+// GotoLine scrolls to line n of the current file's new version, or to the
+// first line after it shown in the diff. ok is false when the diff shows no
+// such line. In the full-file views, it scrolls to line n of the file shown.
+func (m diffTabModel) GotoLine(n int) (diffTabModel, bool) {
+	idx := m.selectedFile()
+	if idx < 0 {
+		return m, false
+	}
+	f := m.file(idx)
+	if m.view != viewDiff {
+		ref, name := m.viewed(f)
+		if n > len(m.contents[contentKey(ref, name)]) {
+			return m, false
+		}
+		m.diffView.SetYOffset(n - 1)
+		return m, true
+	}
+	if m.rows == nil {
+		return m, false
+	}
+	for _, h := range patch.Parse(f.Patch) {
+		for _, l := range h.Lines {
+			if l.New >= n && m.rows[l.Index] >= 0 {
+				m.diffView.SetYOffset(m.rows[l.Index])
+				return m, true
+			}
+		}
+	}
+	return m, false
+}
+
+// Position describes where the viewport is, e.g. "file 2/5 · hunk 1/3".
+func (m diffTabModel) Position() string {
+	idx := m.selectedFile()
+	if idx < 0 {
+		return ""
+	}
+	s := fmt.Sprintf("file %d/%d", idx+1, len(m.files))
+	if len(m.hunks) > 0 {
+		s += fmt.Sprintf(" · hunk %d/%d", max(m.currentHunk(m.hunks), 0)+1, len(m.hunks))
+	}
+	return s
+}
+
+func newLineInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Go to line: "
+	ti.CharLimit = 9
+	return ti
+}
+
+// updateLineInput handles keys while the go-to-line prompt is open.
+func (m AppModel) updateLineInput(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.lineInputActive = false
+		return m, nil
+	case "enter":
+		m.lineInputActive = false
+		value := strings.TrimSpace(m.lineInput.Value())
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			m.notice = fmt.Sprintf("invalid line number %q", value)
+			return m, nil
+		}
+		var ok bool
+		if m.diffTab, ok = m.diffTab.GotoLine(n); !ok {
+			m.notice = fmt.Sprintf("line %d is not in the diff", n)
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.lineInput, cmd = m.lineInput.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+// navFiles has two files: a.go with hunks at rows 0 and 4, and b.go with
+// one hunk.
+var navFiles = []model.DiffFile{
+	{Filename: "a.go", Patch: "@@ -1,2 +1,2 @@\n a\n-b\n+B\n@@ -50,2 +50,3 @@\n x\n+y\n z"},
+	{Filename: "b.go", Patch: "@@ -1 +1 @@\n-c\n+C"},
+}
+
+func navTab(split bool) diffTabModel {
+	d := newDiffTab(200, 5)
+	d.split = split
+	return d.SetFiles(navFiles)
+}
+
+func TestJumpHunk(t *testing.T) {
+	d := navTab(false)
+	steps := []struct {
+		delta    int
+		wantPos  string
+		wantLine string
+	}{
+		{1, "file 1/2 · hunk 2/2", "@@ -50,2 +50,3 @@"},
+		{1, "file 2/2 · hunk 1/1", "@@ -1 +1 @@"},
+		{1, "file 2/2 · hunk 1/1", "@@ -1 +1 @@"},
+		{-1, "file 1/2 · hunk 2/2", "@@ -50,2 +50,3 @@"},
+		{-1, "file 1/2 · hunk 1/2", "@@ -1,2 +1,2 @@"},
+	}
+	for i, s := range steps {
+		d = d.JumpHunk(s.delta)
+		if got := d.Position(); got != s.wantPos {
+			t.Errorf("step %d: Position() = %q, want %q", i, got, s.wantPos)
+		}
+		top := strings.Split(d.diffView.View(), "\n")[0]
+		if !strings.Contains(top, s.wantLine) {
+			t.Errorf("step %d: top line = %q, want %q", i, top, s.wantLine)
+		}
+	}
+}
+
+func TestGotoLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		split   bool
+		line    int
+		wantRow int
+		wantOK  bool
+	}{
+		{"変更行", false, 51, 6, true},
+		{"差分の外は次の行へ", false, 10, 5, true},
+		{"最後より後", false, 60, 0, false},
+		{"左右分割", true, 51, 5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := navTab(tt.split).GotoLine(tt.line)
+			if ok != tt.wantOK || d.diffView.YOffset != tt.wantRow {
+				t.Errorf("GotoLine(%d) = row %d, %v, want row %d, %v", tt.line, d.diffView.YOffset, ok, tt.wantRow, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLineNumbers(t *testing.T) {
+	got := lineNumbers("@@ -9,2 +9,2 @@\n a\n-b\n+B\n\\ No newline at end of file")
+	want := []string{"      ", " 9  9 ", "10    ", "   10 ", "      "}
+	for i := range want {
+		if strip := ansi.Strip(got[i]); strip != want[i] {
+			t.Errorf("line %d gutter = %q, want %q", i, strip, want[i])
+		}
+	}
+}
@@ -2,12 +2,15 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/kosuke9809/gh-review/coverage"
+	"github.com/kosuke9809/gh-review/highlight"
 	"github.com/kosuke9809/gh-review/model"
 )
 
@@ -24,27 +27,28 @@ func ColorDiffLine(line string) string {
 	return line
 }
 
-func colorDiff(patch string) string {
-	lines := strings.Split(patch, "\n")
-	for i, line := range lines {
-		lines[i] = ColorDiffLine(line)
-	}
-	return strings.Join(lines, "\n")
-}
-
 type fileItem struct {
 	name      string
+	previous  string // name before a rename
 	additions int
 	deletions int
+	coverage  string // patch coverage; empty without a profile
 }
 
 func (f fileItem) Title() string {
+	name := f.name
+	if f.previous != "" {
+		name = f.previous + " → " + f.name
+	}
 	if f.additions == 0 && f.deletions == 0 {
-		return f.name
+		return name
 	}
 	adds := lipgloss.NewStyle().Foreground(colorGreen).Render(fmt.Sprintf("+%d", f.additions))
 	dels := lipgloss.NewStyle().Foreground(colorRed).Render(fmt.Sprintf("-%d", f.deletions))
-	return fmt.Sprintf("%s %s/%s", f.name, adds, dels)
+	if f.coverage != "" {
+		return fmt.Sprintf("%s %s/%s %s", name, adds, dels, f.coverage)
+	}
+	return fmt.Sprintf("%s %s/%s", name, adds, dels)
 }
 func (f fileItem) Description() string { return "" }
 func (f fileItem) FilterValue() string { return f.name }
@@ -53,6 +57,23 @@ type diffTabModel struct {
 	fileList  list.Model
 	diffView  viewport.Model
 	files     []model.DiffFile
+	profile   *coverage.Profile // overlaid on added lines when set
+	source    string            // where the diff comes from, when not the API
+	pager     string            // formats patches when set
+	paged     map[pagedKey]pagedOutput
+	syntax    *highlight.Highlighter // colors code when set
+	colored   map[patchKey][]string  // rendered patch lines
+	gutters   map[string][]string    // line-number gutters by patch
+	split     bool                   // side by side when wide enough
+	view      fileView
+	contents  map[string][]string // file lines by contentKey
+	expanded  map[patchKey]string // patches with added context
+	head      string              // commit the diff goes to
+	base      string              // the PR's base commit
+	covered   []coverage.Summary  // patch coverage by file, with a profile
+	covTotal  coverage.Summary    // patch coverage of all files
+	rows      []int               // viewport row of each patch line of the file shown
+	hunks     []int               // viewport row of each hunk of the file shown
 	focusLeft bool
 	width     int
 	height    int
@@ -75,6 +96,11 @@ func newDiffTab(width, height int) diffTabModel {
 	return diffTabModel{
 		fileList:  fl,
 		diffView:  dv,
+		paged:     make(map[pagedKey]pagedOutput),
+		colored:   make(map[patchKey][]string),
+		gutters:   make(map[string][]string),
+		contents:  make(map[string][]string),
+		expanded:  make(map[patchKey]string),
 		focusLeft: true,
 		width:     width,
 		height:    height,
@@ -83,30 +109,178 @@ func newDiffTab(width, height int) diffTabModel {
 
 func (m diffTabModel) SetFiles(files []model.DiffFile) diffTabModel {
 	m.files = files
-	items := make([]list.Item, len(files))
-	for i, f := range files {
-		items[i] = fileItem{
+	m = m.summarize()
+	m.fileList.SetItems(m.fileItems())
+	if len(files) > 0 {
+		idx := min(max(m.fileList.Index(), 0), len(files)-1)
+		m.fileList.Select(idx)
+		m = m.show(idx)
+	}
+	return m
+}
+
+// ForgetFiles drops what was rendered or fetched for the files of the
+// previous PR, so the caches hold one PR at a time.
+func (m diffTabModel) ForgetFiles() diffTabModel {
+	m.colored = make(map[patchKey][]string)
+	m.gutters = make(map[string][]string)
+	m.contents = make(map[string][]string)
+	m.expanded = make(map[patchKey]string)
+	m.paged = make(map[pagedKey]pagedOutput)
+	return m
+}
+
+// SetCoverage overlays p on the added lines, or removes the overlay when p is
+// nil, keeping the current file and scroll position.
+func (m diffTabModel) SetCoverage(p *coverage.Profile) diffTabModel {
+	m.profile = p
+	m = m.summarize()
+	m.fileList.SetItems(m.fileItems())
+	if idx := m.fileList.Index(); idx >= 0 && idx < len(m.files) {
+		m = m.show(idx)
+	}
+	return m
+}
+
+// PageCmd runs the pager on the selected file's patch unless its output is
+// known or being computed.
+func (m diffTabModel) PageCmd() tea.Cmd {
+	idx := m.fileList.Index()
+	if m.pager == "" || idx < 0 || idx >= len(m.files) || m.files[idx].Patch == "" {
+		return nil
+	}
+	f := m.file(idx)
+	key := m.pagedKey(f)
+	if _, ok := m.paged[key]; ok {
+		return nil
+	}
+	m.paged[key] = pagedOutput{pending: true}
+	script := m.pager
+	return func() tea.Msg {
+		out, err := runPager(script, key.width, pagerInput(f))
+		return pagedMsg{key: key, out: out, err: err}
+	}
+}
+
+// SetPaged records the pager output of a patch, showing it when the patch
+// is the selected file's.
+func (m diffTabModel) SetPaged(msg pagedMsg) diffTabModel {
+	m.paged[msg.key] = pagedOutput{out: msg.out, err: msg.err}
+	if idx := m.fileList.Index(); idx >= 0 && idx < len(m.files) && m.pagedKey(m.file(idx)) == msg.key {
+		m = m.show(idx)
+	}
+	return m
+}
+
+func (m diffTabModel) pagedKey(f model.DiffFile) pagedKey {
+	return pagedKey{name: f.Filename, patch: f.Patch, width: m.diffView.Width}
+}
+
+func (m diffTabModel) fileItems() []list.Item {
+	items := make([]list.Item, len(m.files))
+	for i, f := range m.files {
+		item := fileItem{
 			name:      f.Filename,
+			previous:  f.Previous,
 			additions: f.Additions,
 			deletions: f.Deletions,
 		}
+		if m.profile != nil {
+			item.coverage = coveragePercent(m.covered[i])
+		}
+		items[i] = item
 	}
-	m.fileList.SetItems(items)
-	if len(files) > 0 {
-		m.diffView.SetContent(colorDiff(files[0].Patch))
+	return items
+}
+
+// summarize counts the added lines of each file that ran, once per change
+// of files or profile rather than on every frame.
+func (m diffTabModel) summarize() diffTabModel {
+	m.covered, m.covTotal = nil, coverage.Summary{}
+	if m.profile == nil {
+		return m
+	}
+	m.covered = make([]coverage.Summary, len(m.files))
+	for i, f := range m.files {
+		m.covered[i] = coverage.Summarize(coverage.Annotate(f.Patch, m.profile.Lines(f.Filename)))
+		m.covTotal = m.covTotal.Add(m.covered[i])
 	}
 	return m
 }
 
+// CoverageSummary returns the patch coverage of all files. ok is false
+// without a profile.
+func (m diffTabModel) CoverageSummary() (s coverage.Summary, ok bool) {
+	return m.covTotal, m.profile != nil
+}
+
+// showsPaged reports whether f is shown as pager output. Its lines do not
+// match the patch's, so it is not used with a coverage gutter.
+func (m diffTabModel) showsPaged(f model.DiffFile) bool {
+	if m.pager == "" || m.profile != nil {
+		return false
+	}
+	p, ok := m.paged[m.pagedKey(f)]
+	return ok && !p.pending && p.err == nil
+}
+
+// renderPatch colors f's patch, with line numbers, and a coverage gutter
+// when a profile is set. Pager output, once ready, takes the place of both
+// the unified and split views. In the full-file views, the file is shown
+// instead.
+func (m diffTabModel) renderPatch(f model.DiffFile) string {
+	if m.view != viewDiff {
+		return m.renderFile(f)
+	}
+	if f.Patch == "" {
+		return lipgloss.NewStyle().Foreground(colorGray).Render("No patch for this file (binary, or too large for the API)")
+	}
+	if m.showsPaged(f) {
+		return m.paged[m.pagedKey(f)].out
+	}
+	if m.splitActive() {
+		return m.renderSplit(f)
+	}
+	// Copied, as rendered lines are cached.
+	lines := slices.Clone(m.colorPatch(f))
+	var marks []coverage.Mark
+	if m.profile != nil {
+		marks = coverage.Annotate(f.Patch, m.profile.Lines(f.Filename))
+	}
+	nums, ok := m.gutters[f.Patch]
+	if !ok {
+		nums = lineNumbers(f.Patch)
+		m.gutters[f.Patch] = nums
+	}
+	for i := range lines {
+		if marks != nil {
+			lines[i] = coverageGutter(marks[i]) + lines[i]
+		}
+		lines[i] = nums[i] + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m diffTabModel) updateDiffView() diffTabModel {
 	idx := m.fileList.Index()
 	if idx >= 0 && idx < len(m.files) {
-		m.diffView.SetContent(colorDiff(m.files[idx].Patch))
+		m.view = viewDiff
+		m = m.show(idx)
 		m.diffView.GotoTop()
 	}
 	return m
 }
 
+// show renders the file at idx in the viewport and records the rows of its
+// lines and hunks for navigation.
+func (m diffTabModel) show(idx int) diffTabModel {
+	f := m.file(idx)
+	m.diffView.SetContent(m.renderPatch(f))
+	m.rows = m.patchRows(f)
+	m.hunks = m.hunkRows(f, m.rows)
+	return m
+}
+
 func (m diffTabModel) Update(msg tea.Msg) (diffTabModel, tea.Cmd) {
 	var cmd tea.Cmd
 	if m.focusLeft {
@@ -134,7 +308,7 @@ func (m diffTabModel) Update(msg tea.Msg) (diffTabModel, tea.Cmd) {
 		}
 		m.diffView, cmd = m.diffView.Update(msg)
 	}
-	return m, cmd
+	return m, tea.Batch(cmd, m.PageCmd())
 }
 
 func (m diffTabModel) View() string {
@@ -149,6 +323,27 @@ func (m diffTabModel) View() string {
 		rightBorder = rightBorder.BorderForeground(colorGreen)
 		m.fileList.Title = "  Files"
 	}
+	if m.source != "" {
+		m.fileList.Title += " · " + m.source
+	}
+	switch {
+	case m.view == viewHead:
+		m.fileList.Title += " · file at head"
+	case m.view == viewBase:
+		m.fileList.Title += " · file at base"
+	case m.splitActive():
+		m.fileList.Title += " · split"
+	case m.split:
+		m.fileList.Title += " · unified (too narrow to split)"
+	}
+	if fields := strings.Fields(m.pager); len(fields) > 0 {
+		m.fileList.Title += " · " + fields[0]
+	}
+	if s, ok := m.CoverageSummary(); ok {
+		if pct, ok := s.Percent(); ok {
+			m.fileList.Title += fmt.Sprintf(" · %.0f%% covered", pct)
+		}
+	}
 
 	left := leftBorder.Width(leftW).Render(m.fileList.View())
 	right := rightBorder.Width(m.width - leftW - 4).Render(m.diffView.View())
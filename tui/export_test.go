@@ -0,0 +1,4 @@
+package tui
+
+// NewPRsTab exposes the PR list to the external tests in prs_tab_test.go.
+var NewPRsTab = newPRsTab
@@ -0,0 +1,299 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kosuke9809/gh-review/git"
+	"github.com/kosuke9809/gh-review/github"
+	"github.com/kosuke9809/gh-review/model"
+	"github.com/kosuke9809/gh-review/patch"
+)
+
+// expandStep is how many lines of context < and > add to a hunk.
+const expandStep = 10
+
+// fileView is what the diff viewport shows of the selected file.
+type fileView int
+
+const (
+	viewDiff fileView = iota
+	viewHead          // the whole file at the diff's head
+	viewBase          // the whole file at the merge base the diff starts from
+)
+
+// mergeBaseMsg carries the merge base of a PR's base and head commits.
+type mergeBaseMsg struct {
+	prNumber int
+	key      string
+	sha      string
+	err      error
+}
+
+func mergeBaseKey(base, head string) string {
+	return base + "..." + head
+}
+
+// fileContentMsg carries the lines of a file at a commit. expand is the
+// expansion of the current hunk to make once it is loaded: -1 above, 1
+// below, 0 none.
+type fileContentMsg struct {
+	prNumber int
+	name     string
+	ref      string
+	lines    []string
+	expand   int
+	err      error
+}
+
+func contentKey(ref, name string) string {
+	return ref + ":" + name
+}
+
+// file returns the files[idx] with the context added by ExpandHunk.
+func (m diffTabModel) file(idx int) model.DiffFile {
+	f := m.files[idx]
+	if p, ok := m.expanded[patchKey{name: f.Filename, patch: f.Patch}]; ok {
+		f.Patch = p
+	}
+	return f
+}
+
+// ExpandHunk adds expandStep lines of context above the hunk at the top of
+// the viewport, or below it when dir is positive. ok is false when the file
+// at the diff's head is not loaded.
+func (m diffTabModel) ExpandHunk(dir int) (diffTabModel, bool) {
+	idx := m.selectedFile()
+	if idx < 0 || m.view != viewDiff {
+		return m, true
+	}
+	orig, f := m.files[idx], m.file(idx)
+	lines, ok := m.contents[contentKey(m.head, f.Filename)]
+	if !ok {
+		return m, false
+	}
+	hunk := max(m.currentHunk(m.hunks), 0)
+	above, below := expandStep, 0
+	if dir > 0 {
+		above, below = 0, expandStep
+	}
+	m.expanded[patchKey{name: orig.Filename, patch: orig.Patch}] = patch.Expand(f.Patch, lines, hunk, above, below)
+	m = m.show(idx)
+	return m, true
+}
+
+// SetView shows the selected file's diff, or the whole file at the diff's
+// head or base. ok is false when that file is not loaded yet.
+func (m diffTabModel) SetView(v fileView) (diffTabModel, bool) {
+	m.view = v
+	idx := m.selectedFile()
+	if idx < 0 {
+		return m, true
+	}
+	m = m.show(idx)
+	m.diffView.GotoTop()
+	if v == viewDiff {
+		return m, true
+	}
+	ref, name := m.viewed(m.files[idx])
+	_, ok := m.contents[contentKey(ref, name)]
+	return m, ok
+}
+
+// viewed returns the commit and name of f in the full-file view.
+func (m diffTabModel) viewed(f model.DiffFile) (ref, name string) {
+	if m.view == viewBase {
+		if f.Previous != "" {
+			return m.base, f.Previous
+		}
+		return m.base, f.Filename
+	}
+	return m.head, f.Filename
+}
+
+// changedLines returns the lines of f that the diff adds, or in the base
+// view, deletes.
+func (m diffTabModel) changedLines(f model.DiffFile) map[int]bool {
+	changed := make(map[int]bool)
+	for _, h := range patch.Parse(f.Patch) {
+		for _, l := range h.Lines {
+			switch {
+			case m.view == viewBase && l.Kind == patch.Deleted:
+				changed[l.Old] = true
+			case m.view != viewBase && l.Kind == patch.Added:
+				changed[l.New] = true
+			}
+		}
+	}
+	return changed
+}
+
+// changedRows returns the first row of each run of changed lines in the
+// full-file view.
+func (m diffTabModel) changedRows(f model.DiffFile) []int {
+	changed := m.changedLines(f)
+	var rows []int
+	for n := range changed {
+		if !changed[n-1] {
+			rows = append(rows, n-1)
+		}
+	}
+	slices.Sort(rows)
+	return rows
+}
+
+// renderFile renders the whole of f with its line numbers, highlighting the
+// lines the diff changes.
+func (m diffTabModel) renderFile(f model.DiffFile) string {
+	ref, name := m.viewed(f)
+	if ref == "" {
+		return lipgloss.NewStyle().Foreground(colorGray).Render("Finding the merge base…")
+	}
+	lines, ok := m.contents[contentKey(ref, name)]
+	if !ok {
+		return lipgloss.NewStyle().Foreground(colorGray).Render(fmt.Sprintf("Loading %s at %s…", name, git.ShortSHA(ref)))
+	}
+	marker := "+"
+	if m.view == viewBase {
+		marker = "-"
+	}
+	changed := m.changedLines(f)
+	// Rendered as a patch of the whole file for the diff colors.
+	var b strings.Builder
+	b.WriteString("@@")
+	for i, l := range lines {
+		b.WriteByte('\n')
+		if changed[i+1] {
+			b.WriteString(marker)
+		} else {
+			b.WriteByte(' ')
+		}
+		b.WriteString(l)
+	}
+	colored := m.colorPatch(model.DiffFile{Filename: name, Patch: b.String()})
+	numW := len(fmt.Sprint(len(lines)))
+	out := make([]string, len(lines))
+	for i := range lines {
+		out[i] = styleLineNum.Render(fmt.Sprintf("%*d ", numW, i+1)) + colored[i+1]
+	}
+	return strings.Join(out, "\n")
+}
+
+// fileContentCmd loads name as committed at ref: with git when the commit is
+// local, and with the contents API otherwise.
+func (m AppModel) fileContentCmd(pr model.PR, name, ref string, expand int) tea.Cmd {
+	repoRoot, client, owner, repo := m.repoRoot, m.ghClient, m.repoOwner, m.repoRepo
+	return func() tea.Msg {
+		msg := fileContentMsg{prNumber: pr.Number, name: name, ref: ref, expand: expand}
+		content, err := git.FileAt(repoRoot, ref, name)
+		if errors.Is(err, git.ErrNoLocalObjects) {
+			content, err = github.FetchFileContent(context.Background(), client, owner, repo, name, ref)
+		}
+		if msg.err = err; err == nil {
+			msg.lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+		}
+		return msg
+	}
+}
+
+// mergeBaseCmd finds the merge base of pr's base and head, with git when
+// both commits are local, and with the compare API otherwise.
+func (m AppModel) mergeBaseCmd(pr model.PR, head string) tea.Cmd {
+	repoRoot, client, owner, repo := m.repoRoot, m.ghClient, m.repoOwner, m.repoRepo
+	return func() tea.Msg {
+		msg := mergeBaseMsg{prNumber: pr.Number, key: mergeBaseKey(pr.BaseSHA, head)}
+		msg.sha, msg.err = git.MergeBase(repoRoot, pr.BaseSHA, head)
+		if errors.Is(msg.err, git.ErrNoLocalObjects) {
+			msg.sha, msg.err = github.FetchMergeBase(context.Background(), client, owner, repo, pr.BaseSHA, head)
+		}
+		return msg
+	}
+}
+
+// applyMergeBase records a merge base, and loads the selected file at it
+// when the base view waits for it.
+func (m AppModel) applyMergeBase(msg mergeBaseMsg) (AppModel, tea.Cmd) {
+	if msg.err != nil {
+		m.notice = fmt.Sprintf("#%d merge base: %v", msg.prNumber, msg.err)
+		return m, nil
+	}
+	m.mergeBases[msg.key] = msg.sha
+	pr := m.selectedPR
+	if pr == nil || pr.Number != msg.prNumber || mergeBaseKey(pr.BaseSHA, m.diffTab.head) != msg.key {
+		return m, nil
+	}
+	m.diffTab.base = msg.sha
+	if m.diffTab.view != viewBase {
+		return m, nil
+	}
+	var ok bool
+	if m.diffTab, ok = m.diffTab.SetView(viewBase); ok {
+		return m, nil
+	}
+	ref, name := m.diffTab.viewed(m.diffTab.files[m.diffTab.selectedFile()])
+	return m, m.fileContentCmd(*pr, name, ref, 0)
+}
+
+// showFile switches the Diff tab to view v, or back to the diff when v is
+// already shown, loading the file when needed.
+func (m AppModel) showFile(v fileView) (AppModel, tea.Cmd) {
+	if m.selectedPR == nil {
+		return m, nil
+	}
+	if m.diffTab.view == v {
+		v = viewDiff
+	}
+	var ok bool
+	if m.diffTab, ok = m.diffTab.SetView(v); ok {
+		return m, nil
+	}
+	ref, name := m.diffTab.viewed(m.diffTab.files[m.diffTab.selectedFile()])
+	if ref == "" {
+		return m, m.mergeBaseCmd(*m.selectedPR, m.diffTab.head)
+	}
+	return m, m.fileContentCmd(*m.selectedPR, name, ref, 0)
+}
+
+// expandHunk adds context to the hunk at the top of the Diff tab, loading
+// the file at the diff's head when needed.
+func (m AppModel) expandHunk(dir int) (AppModel, tea.Cmd) {
+	if m.selectedPR == nil {
+		return m, nil
+	}
+	var ok bool
+	if m.diffTab, ok = m.diffTab.ExpandHunk(dir); ok {
+		return m, nil
+	}
+	name := m.diffTab.files[m.diffTab.selectedFile()].Filename
+	return m, m.fileContentCmd(*m.selectedPR, name, m.diffTab.head, dir)
+}
+
+// applyFileContent shows a loaded file, and makes the expansion that was
+// waiting for it.
+func (m AppModel) applyFileContent(msg fileContentMsg) AppModel {
+	if msg.err != nil {
+		m.notice = fmt.Sprintf("#%d %s: %v", msg.prNumber, msg.name, msg.err)
+		return m
+	}
+	m.diffTab.contents[contentKey(msg.ref, msg.name)] = msg.lines
+	if m.selectedPR == nil || m.selectedPR.Number != msg.prNumber {
+		return m
+	}
+	idx := m.diffTab.selectedFile()
+	if idx < 0 {
+		return m
+	}
+	if msg.expand != 0 {
+		if m.diffTab.files[idx].Filename == msg.name {
+			m.diffTab, _ = m.diffTab.ExpandHunk(msg.expand)
+		}
+		return m
+	}
+	m.diffTab = m.diffTab.show(idx)
+	return m
+}
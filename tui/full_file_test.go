@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+var fullFiles = []model.DiffFile{
+	{Filename: "a.txt", Patch: "@@ -3,2 +3,2 @@\n 3\n-x\n+4"},
+}
+
+func fullTab(lines []string) diffTabModel {
+	d := newDiffTab(200, 20)
+	d.head, d.base = "headsha", "basesha"
+	if lines != nil {
+		d.contents[contentKey("headsha", "a.txt")] = lines
+	}
+	return d.SetFiles(fullFiles)
+}
+
+func TestExpandHunk(t *testing.T) {
+	lines := []string{"1", "2", "3", "4", "5", "6"}
+	tests := []struct {
+		name   string
+		lines  []string
+		dir    int
+		want   string
+		wantOK bool
+	}{
+		{"上に広げる", lines, -1, "@@ -1,4 +1,4 @@\n 1\n 2\n 3\n-x\n+4", true},
+		{"下に広げる", lines, 1, "@@ -3,4 +3,4 @@\n 3\n-x\n+4\n 5\n 6", true},
+		{"ファイル未取得", nil, 1, fullFiles[0].Patch, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := fullTab(tt.lines).ExpandHunk(tt.dir)
+			if ok != tt.wantOK {
+				t.Errorf("ExpandHunk() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got := d.file(0).Patch; got != tt.want {
+				t.Errorf("file(0).Patch =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{"変更行に印", []string{"1", "2", "3", "4", "5"}, []string{"1  1", "2  2", "3  3", "4 +4", "5  5"}},
+		{"読み込み中", nil, []string{"Loading a.txt at headsha…"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _ := fullTab(tt.lines).SetView(viewHead)
+			got := strings.Split(ansi.Strip(d.renderFile(d.file(0))), "\n")
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("renderFile() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+			if rows := d.hunks; tt.lines != nil && (len(rows) != 1 || rows[0] != 3) {
+				t.Errorf("hunkRows() = %v, want [3]", rows)
+			}
+		})
+	}
+}
+
+func TestApplyMergeBase(t *testing.T) {
+	pr := model.PR{Number: 3, BaseSHA: "tipsha"}
+	m := AppModel{selectedPR: &pr, mergeBases: make(map[string]string), diffTab: fullTab(nil)}
+	m.diffTab.base = ""
+	m.diffTab, _ = m.diffTab.SetView(viewBase)
+	if got := ansi.Strip(m.diffTab.renderFile(m.diffTab.file(0))); got != "Finding the merge base…" {
+		t.Errorf("renderFile() = %q before the merge base is known", got)
+	}
+
+	m, cmd := m.applyMergeBase(mergeBaseMsg{prNumber: 3, key: mergeBaseKey("tipsha", "headsha"), sha: "forksha"})
+	if m.diffTab.base != "forksha" || m.mergeBases[mergeBaseKey("tipsha", "headsha")] != "forksha" {
+		t.Errorf("base = %q, mergeBases = %v; want forksha", m.diffTab.base, m.mergeBases)
+	}
+	if cmd == nil {
+		t.Error("file at the merge base not loaded")
+	}
+	if ref, _ := m.diffTab.viewed(m.diffTab.file(0)); ref != "forksha" {
+		t.Errorf("viewed() ref = %q, want forksha", ref)
+	}
+}
@@ -0,0 +1,268 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kosuke9809/gh-review/gotest"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+// goTestRunName names affected-package test runs in a PR's local-check badge.
+const goTestRunName = "go test (affected)"
+
+// testsPlannedMsg carries the packages affected by a PR. ch delivers the
+// test run's next message.
+type testsPlannedMsg struct {
+	pkgs []string
+	err  error
+	ch   <-chan tea.Msg
+}
+
+// testsOutputMsg is a line of `go test -json` output.
+type testsOutputMsg struct {
+	line string
+	ch   <-chan tea.Msg
+}
+
+// testsDoneMsg reports that go test exited.
+type testsDoneMsg struct {
+	prNumber int
+	head     string
+	code     int
+	err      error
+}
+
+// goTestTabModel is the Tests screen: the per-package results of running the
+// tests affected by a PR in its worktree.
+type goTestTabModel struct {
+	viewport viewport.Model
+	prNumber int
+	report   *gotest.Report
+	planning bool // looking up affected packages
+	running  bool
+	cancel   context.CancelFunc
+	err      error
+	width    int
+	height   int
+}
+
+func newGoTestTab(width, height int) goTestTabModel {
+	return goTestTabModel{viewport: viewport.New(width, height), width: width, height: height}
+}
+
+// Resize returns the tab with the given size, keeping its results.
+func (m goTestTabModel) Resize(width, height int) goTestTabModel {
+	m.viewport = viewport.New(width, height)
+	m.width, m.height = width, height
+	return m.refresh()
+}
+
+// refresh re-renders the results, keeping the scroll position.
+func (m goTestTabModel) refresh() goTestTabModel {
+	m.viewport.SetContent(RenderGoTestReport(m.report, m.err))
+	return m
+}
+
+// Cancel stops the running tests, if any.
+func (m goTestTabModel) Cancel() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+}
+
+// summary describes the run for the top border.
+func (m goTestTabModel) summary() string {
+	switch {
+	case m.planning:
+		return styleCIPending.Render("finding affected packages")
+	case m.report == nil:
+		return ""
+	}
+	r := m.report
+	s := fmt.Sprintf("%d packages", len(r.Results))
+	if n := r.Count(gotest.StatusPass); n > 0 {
+		s += "  " + styleCIPass.Render(fmt.Sprintf("%d passed", n))
+	}
+	if n := r.Count(gotest.StatusFail); n > 0 {
+		s += "  " + styleCIFail.Render(fmt.Sprintf("%d failed", n))
+	}
+	if m.running {
+		s += "  " + styleCIPending.Render("running")
+	}
+	return s
+}
+
+// RenderGoTestReport renders a per-package results table followed by the
+// output of the failures.
+func RenderGoTestReport(r *gotest.Report, err error) string {
+	var b strings.Builder
+	if err != nil {
+		b.WriteString(styleCIFail.Render("Error: "+err.Error()) + "\n\n")
+	}
+	if r == nil {
+		return b.String()
+	}
+	if len(r.Results) == 0 && err == nil {
+		return "No Go packages affected by this PR\n"
+	}
+	width := 0
+	for _, res := range r.Results {
+		width = max(width, len(res.Package))
+	}
+	for _, res := range r.Results {
+		elapsed := ""
+		if res.Elapsed > 0 {
+			elapsed = res.Elapsed.String()
+		}
+		b.WriteString(fmt.Sprintf("%s %-*s  %8s", goTestIcon(res.Status), width, res.Package, elapsed))
+		switch {
+		case len(res.Failed) > 0:
+			b.WriteString("  " + styleCIFail.Render(strings.Join(res.Failed, ", ")))
+		case res.Status == gotest.StatusSkip:
+			b.WriteString("  " + lipgloss.NewStyle().Foreground(colorGray).Render("no test files"))
+		}
+		b.WriteString("\n")
+	}
+	sep := lipgloss.NewStyle().Foreground(colorGray).Render(strings.Repeat("─", 60))
+	for _, res := range r.Results {
+		if res.Status != gotest.StatusFail || len(res.Output) == 0 {
+			continue
+		}
+		b.WriteString("\n" + sep + "\n")
+		b.WriteString(lipgloss.NewStyle().Bold(true).Render(res.Package) + "\n")
+		for _, line := range res.Output {
+			b.WriteString(line + "\n")
+		}
+	}
+	if len(r.Errors) > 0 {
+		b.WriteString("\n" + sep + "\n")
+		for _, line := range r.Errors {
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
+
+func goTestIcon(s gotest.Status) string {
+	switch s {
+	case gotest.StatusPass:
+		return styleCIPass.Render("✓")
+	case gotest.StatusFail:
+		return styleCIFail.Render("✗")
+	case gotest.StatusRunning:
+		return styleCIPending.Render("●")
+	case gotest.StatusSkip:
+		return lipgloss.NewStyle().Foreground(colorGray).Render("-")
+	}
+	return lipgloss.NewStyle().Foreground(colorGray).Render("○")
+}
+
+func (m goTestTabModel) Update(msg tea.Msg) (goTestTabModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m goTestTabModel) View() string {
+	return m.viewport.View()
+}
+
+// startGoTests runs the tests of the Go packages affected by the PR shown in
+// the detail screen, in its worktree.
+func (m AppModel) startGoTests() (AppModel, tea.Cmd) {
+	pr := m.selectedPR
+	if pr == nil {
+		return m, nil
+	}
+	switch {
+	case !pr.HasWorktree:
+		m.notice = fmt.Sprintf("#%d has no worktree  [w]orktree", pr.Number)
+		return m, nil
+	case !pr.FilesLoaded:
+		m.notice = "the diff is still loading"
+		return m, nil
+	case m.testsTab.planning || m.testsTab.running:
+		if m.testsTab.prNumber == pr.Number {
+			m.screen = screenTests
+			return m, nil
+		}
+		m.notice = fmt.Sprintf("#%d is running tests", m.testsTab.prNumber)
+		return m, nil
+	}
+	files := make([]string, len(pr.DiffFiles))
+	for i, f := range pr.DiffFiles {
+		files[i] = f.Filename
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.testsTab.prNumber = pr.Number
+	m.testsTab.report, m.testsTab.err = nil, nil
+	m.testsTab.planning, m.testsTab.cancel = true, cancel
+	m.testsTab.viewport.GotoTop()
+	m.testsTab = m.testsTab.refresh()
+	m.screen = screenTests
+
+	ch := make(chan tea.Msg, 64)
+	prNum, path, head := pr.Number, pr.WorktreePath, pr.WorktreeHEAD
+	go func() {
+		defer close(ch)
+		defer cancel()
+		mods, err := gotest.AffectedModules(ctx, path, files)
+		var pkgs []string
+		for _, mod := range mods {
+			pkgs = append(pkgs, mod.Packages...)
+		}
+		ch <- testsPlannedMsg{pkgs: pkgs, err: err, ch: ch}
+		if err != nil || len(pkgs) == 0 {
+			return
+		}
+		code, err := gotest.RunModules(ctx, mods, func(line string) { ch <- testsOutputMsg{line: line, ch: ch} })
+		ch <- testsDoneMsg{prNumber: prNum, head: head, code: code, err: err}
+	}()
+	return m, waitForMsg(ch)
+}
+
+// updateGoTests applies a message of the running tests.
+func (m AppModel) updateGoTests(msg tea.Msg) (AppModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case testsPlannedMsg:
+		m.testsTab.planning = false
+		m.testsTab.err = msg.err
+		if msg.err == nil {
+			m.testsTab.report = gotest.NewReport(msg.pkgs)
+			m.testsTab.running = len(msg.pkgs) > 0
+		}
+		m.testsTab = m.testsTab.refresh()
+		if !m.testsTab.running {
+			// Nothing runs, so there is nothing left to cancel.
+			m.testsTab.cancel = nil
+			return m, nil
+		}
+		return m, waitForMsg(msg.ch)
+	case testsOutputMsg:
+		m.testsTab.report.Add(msg.line)
+		m.testsTab = m.testsTab.refresh()
+		return m, waitForMsg(msg.ch)
+	case testsDoneMsg:
+		m.testsTab.running, m.testsTab.cancel = false, nil
+		if errors.Is(msg.err, context.Canceled) {
+			m.testsTab.err = errors.New("cancelled")
+		} else {
+			m.testsTab.err = msg.err
+		}
+		m.testsTab = m.testsTab.refresh()
+		if msg.err != nil {
+			return m, nil
+		}
+		if m.screen != screenTests {
+			m.notice = fmt.Sprintf("#%d %s: %d failed", msg.prNumber, goTestRunName, m.testsTab.report.Count(gotest.StatusFail))
+		}
+		return m.recordLocalRun(model.LocalRun{Name: goTestRunName, ExitCode: msg.code, HeadSHA: msg.head}, msg.prNumber)
+	}
+	return m, nil
+}
@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kosuke9809/gh-review/gotest"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestUpdateGoTests(t *testing.T) {
+	m := AppModel{repoName: "octo/app", prsTab: newPRsTab(80, 20, model.WorkSchedule{}), testsTab: newGoTestTab(80, 20)}
+	m.testsTab.prNumber, m.testsTab.planning = 5, true
+
+	m, cmd := m.updateGoTests(testsPlannedMsg{pkgs: []string{"m/a", "m/b"}})
+	if !m.testsTab.running || cmd == nil {
+		t.Fatal("tests not running after the packages were planned")
+	}
+	for _, line := range []string{
+		`{"Action":"pass","Package":"m/a","Elapsed":0.1}`,
+		`{"Action":"output","Package":"m/b","Test":"TestB","Output":"    b_test.go:3: boom\n"}`,
+		`{"Action":"fail","Package":"m/b","Test":"TestB"}`,
+		`{"Action":"fail","Package":"m/b","Elapsed":0.2}`,
+	} {
+		m, _ = m.updateGoTests(testsOutputMsg{line: line})
+	}
+	m, _ = m.updateGoTests(testsDoneMsg{prNumber: 5, head: "abc", code: 1})
+
+	if m.testsTab.running {
+		t.Error("tests still running after go test exited")
+	}
+	if got := m.testsTab.report.Count(gotest.StatusFail); got != 1 {
+		t.Errorf("failed packages = %d, want 1", got)
+	}
+	if r := m.state.LocalRuns["octo/app#5"]; r.Name != goTestRunName || r.ExitCode != 1 || r.HeadSHA != "abc" {
+		t.Errorf("LocalRuns[octo/app#5] = %+v, want failed %s", r, goTestRunName)
+	}
+	content := RenderGoTestReport(m.testsTab.report, nil)
+	for _, want := range []string{"m/a", "TestB", "b_test.go:3: boom"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("report does not contain %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestUpdateGoTests_NothingToRun(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  testsPlannedMsg
+	}{
+		{"影響なし", testsPlannedMsg{}},
+		{"計画の失敗", testsPlannedMsg{err: errors.New("go list: boom")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := AppModel{testsTab: newGoTestTab(80, 20)}
+			m.testsTab.planning, m.testsTab.cancel = true, func() {}
+			m, _ = m.updateGoTests(tt.msg)
+			if m.testsTab.running || m.testsTab.cancel != nil {
+				t.Errorf("running = %v, cancel set = %v; want neither", m.testsTab.running, m.testsTab.cancel != nil)
+			}
+		})
+	}
+}
+
+func TestRenderGoTestReport_NoPackages(t *testing.T) {
+	if got := RenderGoTestReport(gotest.NewReport(nil), nil); !strings.Contains(got, "No Go packages affected") {
+		t.Errorf("RenderGoTestReport() = %q", got)
+	}
+}
@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyHelp is a line of the ? help overlay.
+type keyHelp struct {
+	key  string
+	desc string
+}
+
+// keyHelps returns every key of the current screen; the bottom border only
+// has room for the essential ones.
+func (m AppModel) keyHelps() []keyHelp {
+	back := []keyHelp{{"Esc/b", "back"}, {"?", "close this help"}, {"q", "quit"}}
+	switch m.screen {
+	case screenList:
+		return []keyHelp{
+			{"j/k", "move"},
+			{"Enter", "open the PR"},
+			{"w", "create a worktree at the PR head"},
+			{"B", "create a worktree on a branch that pushes to the PR"},
+			{"U", "update the worktree to the PR head"},
+			{"o", "open the worktree in the editor"},
+			{"D", "delete the worktree"},
+			{"u", "undo the last delete"},
+			{"x", "cancel the worktree's hooks"},
+			{"f", "next filter"},
+			{"s/S", "next sort / reverse the sort"},
+			{"z", "snooze"},
+			{"Z", "show or hide snoozed PRs"},
+			{"t", "stats"},
+			{"v", "reviewers"},
+			{"W", "worktrees"},
+			{"R", "run a command in the worktree"},
+			{"L", "log"},
+			{"r", "refresh"},
+			{"Esc/b", "clear the reviewer filter"},
+			{"?", "close this help"},
+			{"q", "quit"},
+		}
+	case screenReviewers:
+		return append([]keyHelp{{"j/k", "move"}, {"Enter", "show the reviewer's PRs"}, {"r", "refresh"}}, back...)
+	case screenLog:
+		return append([]keyHelp{{"j/k", "scroll"}, {"c", "clear"}}, back...)
+	case screenRun:
+		return append([]keyHelp{
+			{"1-9", "run a preset"},
+			{"!", "run a command"},
+			{"x", "cancel"},
+			{"j/k", "scroll"},
+			{"c", "clear"},
+		}, back...)
+	case screenTests:
+		return append([]keyHelp{{"x", "cancel"}, {"r", "rerun"}, {"j/k", "scroll"}}, back...)
+	case screenWorktrees:
+		return append([]keyHelp{
+			{"j/k", "move"},
+			{"Space", "mark"},
+			{"D", "delete the marked worktrees"},
+			{"u", "undo the last delete"},
+			{"P", "prune the worktrees of closed PRs"},
+			{"M", "migrate worktrees to the configured directory"},
+			{"o", "open in the editor"},
+			{"r", "refresh"},
+		}, back...)
+	case screenStats:
+		return append([]keyHelp{{"p", "next period"}, {"j/k", "scroll"}, {"r", "refresh"}}, back...)
+	}
+	keys := []keyHelp{
+		{"tab", "switch between Detail and Diff"},
+		{"R", "run a command in the worktree"},
+		{"T", "test the affected Go packages"},
+		{"C", "toggle coverage"},
+		{"d", "open the difftool"},
+		{"U", "update the worktree to the PR head"},
+		{"x", "cancel the worktree's hooks"},
+	}
+	if m.detailSubTab == subTabDiff {
+		keys = append(keys,
+			keyHelp{"enter", "focus the file list or the diff"},
+			keyHelp{"j/k", "scroll"},
+			keyHelp{"n/N", "next / previous hunk"},
+			keyHelp{"]/[", "next / previous file"},
+			keyHelp{":", "go to line"},
+			keyHelp{"</>", "more context above / below the hunk"},
+			keyHelp{"v/V", "the whole file at the head / merge base"},
+			keyHelp{"w", "ignore whitespace"},
+			keyHelp{"e", "expand to the whole file"},
+			keyHelp{"+/-", "more / less context"},
+			keyHelp{"F", "fetch and diff locally"},
+			keyHelp{"s", "split view"},
+			keyHelp{"|", "render with the pager"},
+		)
+	} else {
+		keys = append(keys, keyHelp{"j/k", "scroll"})
+	}
+	return append(keys, back...)
+}
+
+// renderHelp renders the ? help overlay in place of the screen's body.
+func (m AppModel) renderHelp() string {
+	keys := m.keyHelps()
+	keyW := 0
+	for _, k := range keys {
+		keyW = max(keyW, len(k.key))
+	}
+	styleKey := lipgloss.NewStyle().Foreground(colorCyan).Bold(true)
+	lines := make([]string, len(keys))
+	for i, k := range keys {
+		lines[i] = "  " + styleKey.Render(fmt.Sprintf("%-*s", keyW, k.key)) + "  " + k.desc
+	}
+	return lipgloss.NewStyle().
+		Width(m.width - 2).
+		Height(m.height - 4).
+		Render(strings.Join(lines, "\n"))
+}
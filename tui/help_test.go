@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestHelpOverlay(t *testing.T) {
+	m := AppModel{width: 100, height: 30, screen: screenList, prsTab: newPRsTab(98, 26, model.WorkSchedule{}), logPanel: newLogPanel(98, 26)}
+	next, _ := m.Update(key("?"))
+	m = next.(AppModel)
+	if !m.helpOpen {
+		t.Fatal("? did not open the help")
+	}
+	body := ansi.Strip(m.renderBody())
+	for _, want := range []string{"create a worktree on a branch that pushes to the PR", "worktrees", "log"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("help does not contain %q:\n%s", want, body)
+		}
+	}
+
+	next, _ = m.Update(key("W"))
+	m = next.(AppModel)
+	if m.helpOpen || m.screen != screenList {
+		t.Errorf("helpOpen = %v, screen = %v after a key; want the help closed and the key ignored", m.helpOpen, m.screen)
+	}
+}
+
+func TestHelpStr_Width(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		screen screen
+		sub    detailSubTab
+	}{
+		{"一覧", screenList, subTabDetail},
+		{"詳細", screenDetail, subTabDetail},
+		{"差分", screenDetail, subTabDiff},
+		{"ワークツリー", screenWorktrees, subTabDetail},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := AppModel{screen: tt.screen, detailSubTab: tt.sub}
+			if got := len(m.helpStr()); got > 96 {
+				t.Errorf("helpStr() is %d characters: %s", got, m.helpStr())
+			}
+		})
+	}
+}
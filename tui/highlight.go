@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kosuke9809/gh-review/highlight"
+	"github.com/kosuke9809/gh-review/model"
+	"github.com/kosuke9809/gh-review/worddiff"
+)
+
+// patchKey identifies a file's patch.
+type patchKey struct {
+	name  string
+	patch string
+}
+
+// colorPatch renders f's patch one string per patch line, with syntax
+// highlighting when there is a highlighter for f's language, and the changed
+// words of paired lines emphasized. Results are cached, so moving between
+// files stays fast.
+func (m diffTabModel) colorPatch(f model.DiffFile) []string {
+	key := patchKey{name: f.Filename, patch: f.Patch}
+	if lines, ok := m.colored[key]; ok {
+		return lines
+	}
+	var spans [][]highlight.Span
+	ok := false
+	if m.syntax != nil {
+		spans, ok = m.syntax.Patch(f.Filename, f.Patch)
+	}
+	words := worddiff.Patch(f.Patch)
+	lines := strings.Split(f.Patch, "\n")
+	for i, line := range lines {
+		switch {
+		case line == "" || strings.HasPrefix(line, "@@") || strings.HasPrefix(line, `\`):
+			lines[i] = ColorDiffLine(line)
+		case ok:
+			lines[i] = renderLine(line[0], spans[i], words[i], true)
+		default:
+			lines[i] = renderLine(line[0], []highlight.Span{{Text: line[1:]}}, words[i], false)
+		}
+	}
+	m.colored[key] = lines
+	return lines
+}
+
+// renderLine renders a patch line from its marker and the spans of its text,
+// emphasizing the changed ranges. Highlighted lines get the backgrounds of
+// added and deleted lines; plain ones are colored like ColorDiffLine.
+func renderLine(marker byte, spans []highlight.Span, changed []worddiff.Range, syntax bool) string {
+	base, emph, mark := lipgloss.NewStyle(), lipgloss.NewStyle(), lipgloss.NewStyle()
+	switch {
+	case marker == '+' && syntax:
+		base, emph = base.Background(colorDiffAddBg), emph.Background(colorDiffAddWordBg)
+		mark = styleDiffAdd.Background(colorDiffAddBg)
+	case marker == '-' && syntax:
+		base, emph = base.Background(colorDiffDelBg), emph.Background(colorDiffDelWordBg)
+		mark = styleDiffDel.Background(colorDiffDelBg)
+	case marker == '+':
+		base, emph, mark = styleDiffAdd, styleDiffAdd.Reverse(true), styleDiffAdd
+	case marker == '-':
+		base, emph, mark = styleDiffDel, styleDiffDel.Reverse(true), styleDiffDel
+	}
+	var b strings.Builder
+	b.WriteString(mark.Render(string(marker)))
+	off := 0
+	for _, s := range spans {
+		for _, seg := range segments(s.Text, off, changed) {
+			st := base
+			if seg.changed {
+				st = emph
+			}
+			st = st.Bold(s.Bold).Italic(s.Italic)
+			if s.Color != "" {
+				st = st.Foreground(lipgloss.Color(s.Color))
+			}
+			b.WriteString(st.Render(seg.text))
+		}
+		off += len(s.Text)
+	}
+	return b.String()
+}
+
+// segment is a piece of a span, inside or outside a changed range.
+type segment struct {
+	text    string
+	changed bool
+}
+
+// segments cuts text, found at byte offset off of its line, at the edges of
+// the changed ranges.
+func segments(text string, off int, changed []worddiff.Range) []segment {
+	var out []segment
+	start := 0
+	for _, r := range changed {
+		from, to := max(r.Start-off, start), min(r.End-off, len(text))
+		if from >= to {
+			continue
+		}
+		if from > start {
+			out = append(out, segment{text: text[start:from]})
+		}
+		out = append(out, segment{text: text[from:to], changed: true})
+		start = to
+	}
+	if start < len(text) {
+		out = append(out, segment{text: text[start:]})
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kosuke9809/gh-review/coverage"
+	"github.com/kosuke9809/gh-review/highlight"
+	"github.com/kosuke9809/gh-review/model"
+	"github.com/kosuke9809/gh-review/worddiff"
+)
+
+// sgr returns the SGR parameters style uses, e.g. "48;2;30;58;38".
+func sgr(style lipgloss.Style) string {
+	s := style.Render("x")
+	return s[strings.Index(s, "[")+1 : strings.Index(s, "m")]
+}
+
+// segmentWith reports whether text is rendered with the SGR parameters p in
+// line.
+func segmentWith(line, p, text string) bool {
+	for _, seg := range strings.Split(line, "\x1b[")[1:] {
+		params, body, _ := strings.Cut(seg, "m")
+		if body == text && strings.Contains(params, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestColorPatch(t *testing.T) {
+	h, err := highlight.New("monokai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch := "@@ -1,2 +1,3 @@\n package main\n-var a = 1\n+var a = 2\n+"
+	addBg, delBg := sgr(lipgloss.NewStyle().Background(colorDiffAddBg)), sgr(lipgloss.NewStyle().Background(colorDiffDelBg))
+	addWord, delWord := sgr(lipgloss.NewStyle().Background(colorDiffAddWordBg)), sgr(lipgloss.NewStyle().Background(colorDiffDelWordBg))
+	reverse := sgr(lipgloss.NewStyle().Reverse(true))
+	tests := []struct {
+		name   string
+		syntax *highlight.Highlighter
+		line   int
+		p      string
+		text   string
+	}{
+		{"追加行は緑の背景", h, 3, addBg, "var"},
+		{"空の追加行も背景つき", h, 4, addBg, "+"},
+		{"削除行は赤の背景", h, 2, delBg, "var"},
+		{"変わった語は濃い背景", h, 3, addWord, "2"},
+		{"削除された語は濃い背景", h, 2, delWord, "1"},
+		{"ハイライトなしでは反転", nil, 3, reverse, "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDiffTab(120, 40)
+			d.syntax = tt.syntax
+			lines := d.colorPatch(model.DiffFile{Filename: "main.go", Patch: patch})
+			if !segmentWith(lines[tt.line], tt.p, tt.text) {
+				t.Errorf("line = %q, want %q rendered with %s", lines[tt.line], tt.text, tt.p)
+			}
+			for i, want := range strings.Split(patch, "\n") {
+				if got := ansi.Strip(lines[i]); got != want {
+					t.Errorf("line %d text = %q, want %q", i, got, want)
+				}
+			}
+			if lines[0] != ColorDiffLine("@@ -1,2 +1,3 @@") {
+				t.Errorf("hunk header = %q, want the usual colors", lines[0])
+			}
+		})
+	}
+}
+
+func TestDiffTab_ColorCache(t *testing.T) {
+	h, err := highlight.New("monokai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := newDiffTab(120, 40)
+	d.syntax = h
+	f := model.DiffFile{Filename: "main.go", Patch: "@@ -1 +1 @@\n+var b = 2"}
+	first := d.colorPatch(f)
+	if &d.colorPatch(f)[0] != &first[0] {
+		t.Fatal("colorPatch() did not reuse the cached lines")
+	}
+	want := first[1]
+	// The coverage gutter must not leak into the cache.
+	d = d.SetCoverage(&coverage.Profile{Files: map[string]map[int]bool{"main.go": {1: true}}})
+	_ = d.renderPatch(f)
+	if d.colorPatch(f)[1] != want {
+		t.Error("renderPatch() changed the cached lines")
+	}
+}
+
+func TestSegments(t *testing.T) {
+	// "b := old(a)" with "old" changed, as spans "b", " := ", "old(a)".
+	got := segments("old(a)", 5, []worddiff.Range{{Start: 5, End: 8}})
+	want := []segment{{text: "old", changed: true}, {text: "(a)"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("segments() = %+v, want %+v", got, want)
+	}
+	if got := segments(" := ", 1, []worddiff.Range{{Start: 5, End: 8}}); !reflect.DeepEqual(got, []segment{{text: " := "}}) {
+		t.Errorf("segments() before the range = %+v", got)
+	}
+}
+
+func TestDiffTab_ForgetFiles(t *testing.T) {
+	m := newDiffTab(80, 20)
+	m.colorPatch(model.DiffFile{Filename: "a.go", Patch: "@@ -1 +1 @@\n+x"})
+	if len(m.colored) != 1 {
+		t.Fatalf("colored = %d entries, want the patch cached", len(m.colored))
+	}
+	if m = m.ForgetFiles(); len(m.colored) != 0 {
+		t.Errorf("colored = %d entries after ForgetFiles, want none", len(m.colored))
+	}
+}
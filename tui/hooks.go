@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kosuke9809/gh-review/model"
+	"github.com/kosuke9809/gh-review/runner"
+)
+
+// hookOutputMsg is a line printed by a running hook. ch delivers the
+// hook's next message.
+type hookOutputMsg struct {
+	prNumber int
+	line     string
+	ch       <-chan tea.Msg
+}
+
+// hookDoneMsg reports that the hooks of a PR's worktree finished. failed is
+// the command that failed, if any.
+type hookDoneMsg struct {
+	prNumber int
+	ctx      context.Context // of the run, to tell it from a later one
+	failed   string
+	code     int
+	err      error
+}
+
+// prEnv returns the environment exported to commands run in pr's worktree.
+func prEnv(pr model.PR, path, repoRoot string) []string {
+	return []string{
+		"PR_NUMBER=" + strconv.Itoa(pr.Number),
+		"PR_HEAD_REF=" + pr.HeadRef,
+		"PR_BASE_REF=" + pr.BaseRef,
+		"PR_HEAD_SHA=" + pr.HeadSHA,
+		"PR_AUTHOR=" + pr.Author,
+		"PR_WORKTREE=" + path,
+		"REPO_ROOT=" + repoRoot,
+	}
+}
+
+// runHooksCmd runs scripts in order inside the worktree at path, streaming
+// their output, and stops at the first failure or when ctx is cancelled. It
+// returns nil when there is nothing to run.
+func (m AppModel) runHooksCmd(ctx context.Context, pr model.PR, path, event string, scripts []string) tea.Cmd {
+	if len(scripts) == 0 || path == "" {
+		return nil
+	}
+	ch := make(chan tea.Msg, 64)
+	env := prEnv(pr, path, m.repoRoot)
+	go func() {
+		defer close(ch)
+		out := func(line string) { ch <- hookOutputMsg{prNumber: pr.Number, line: line, ch: ch} }
+		for _, script := range scripts {
+			out(fmt.Sprintf("$ %s  (%s)", script, event))
+			code, err := runner.Run(ctx, runner.Command{Dir: path, Env: env, Script: script}, out)
+			if err != nil || code != 0 {
+				ch <- hookDoneMsg{prNumber: pr.Number, ctx: ctx, failed: script, code: code, err: err}
+				return
+			}
+		}
+		ch <- hookDoneMsg{prNumber: pr.Number, ctx: ctx}
+	}()
+	return waitForMsg(ch)
+}
+
+// waitForMsg delivers the next message from ch.
+func waitForMsg(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+// startHooks marks pr's hooks as running and returns the command that runs
+// them, or nil when none are configured for event.
+func (m AppModel) startHooks(pr model.PR, path, event string, scripts []string) (AppModel, tea.Cmd) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := m.runHooksCmd(ctx, pr, path, event, scripts)
+	if cmd == nil {
+		cancel()
+		return m, nil
+	}
+	m.cancelHooks(pr.Number)
+	m.hookRuns[pr.Number] = hookRun{ctx: ctx, cancel: cancel}
+	m.hookStatus[pr.Number] = model.HookRunning
+	m.notice = fmt.Sprintf("#%d running %s hooks  [x]cancel", pr.Number, event)
+	m = m.applyFilter()
+	return m, cmd
+}
+
+// hookRun is the hooks running in a PR's worktree.
+type hookRun struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// cancelHooks stops the hooks running in a PR's worktree, if any.
+func (m AppModel) cancelHooks(prNumber int) bool {
+	run, ok := m.hookRuns[prNumber]
+	if ok {
+		run.cancel()
+		delete(m.hookRuns, prNumber)
+	}
+	return ok
+}
+
+// finishHooks records the outcome of a PR's hooks. The end of a run that
+// was replaced by a later one is only logged.
+func (m AppModel) finishHooks(msg hookDoneMsg) AppModel {
+	run, ok := m.hookRuns[msg.prNumber]
+	if ok && run.ctx != msg.ctx {
+		m.logPanel = m.logPanel.Append(fmt.Sprintf("[#%d] earlier hooks stopped", msg.prNumber))
+		return m
+	}
+	if ok {
+		run.cancel()
+		delete(m.hookRuns, msg.prNumber)
+	}
+	m.hookStatus[msg.prNumber] = model.HookOK
+	line := "hooks finished"
+	if msg.failed != "" {
+		m.hookStatus[msg.prNumber] = model.HookFailed
+		switch {
+		case errors.Is(msg.err, context.Canceled):
+			line = "hooks cancelled: " + msg.failed
+		case msg.err != nil:
+			line = fmt.Sprintf("hook failed: %s: %v", msg.failed, msg.err)
+		default:
+			line = fmt.Sprintf("hook failed (exit %d): %s", msg.code, msg.failed)
+		}
+		m.notice = fmt.Sprintf("#%d %s  [L]og", msg.prNumber, line)
+	}
+	m.logPanel = m.logPanel.Append(fmt.Sprintf("[#%d] %s", msg.prNumber, line))
+	return m.applyFilter()
+}
+
+func (m AppModel) prByNumber(n int) (model.PR, bool) {
+	for _, pr := range m.allPRs {
+		if pr.Number == n {
+			return pr, true
+		}
+	}
+	return model.PR{}, false
+}
+
+// hooksTarget returns the PR whose hooks x cancels: the selected PR on the
+// List and Detail screens.
+func (m AppModel) hooksTarget() *model.PR {
+	switch m.screen {
+	case screenList:
+		return m.prsTab.SelectedPR()
+	case screenDetail:
+		return m.selectedPR
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+// drainHooks runs cmd and the follow-up waits until the hooks finish,
+// returning the output lines and the final message.
+func drainHooks(t *testing.T, cmd tea.Cmd) ([]string, hookDoneMsg) {
+	t.Helper()
+	var lines []string
+	for cmd != nil {
+		switch msg := cmd().(type) {
+		case hookOutputMsg:
+			lines = append(lines, msg.line)
+			cmd = waitForMsg(msg.ch)
+		case hookDoneMsg:
+			return lines, msg
+		default:
+			t.Fatalf("unexpected message %T", msg)
+		}
+	}
+	t.Fatal("hooks did not finish")
+	return nil, hookDoneMsg{}
+}
+
+func TestRunHooksCmd(t *testing.T) {
+	dir := t.TempDir()
+	m := AppModel{repoRoot: "/repo"}
+	pr := model.PR{Number: 7, HeadRef: "feature", BaseRef: "main"}
+
+	lines, done := drainHooks(t, m.runHooksCmd(context.Background(), pr, dir, "post_create", []string{
+		`echo "$PR_NUMBER $PR_HEAD_REF $PR_BASE_REF $REPO_ROOT"`,
+		"exit 2",
+		"echo never",
+	}))
+	want := []string{
+		`$ echo "$PR_NUMBER $PR_HEAD_REF $PR_BASE_REF $REPO_ROOT"  (post_create)`,
+		"7 feature main /repo",
+		"$ exit 2  (post_create)",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("output = %q, want %q", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+	if done.prNumber != 7 || done.failed != "exit 2" || done.code != 2 {
+		t.Errorf("done = %+v, want failure of exit 2", done)
+	}
+}
+
+func TestRunHooksCmd_None(t *testing.T) {
+	if cmd := (AppModel{}).runHooksCmd(context.Background(), model.PR{Number: 1}, t.TempDir(), "post_create", nil); cmd != nil {
+		t.Error("runHooksCmd() without hooks returned a command")
+	}
+}
+
+func TestStartHooks_Cancel(t *testing.T) {
+	m := AppModel{prsTab: newPRsTab(80, 20, model.WorkSchedule{}), hookStatus: make(map[int]model.HookStatus), hookRuns: make(map[int]hookRun), logPanel: newLogPanel(80, 20)}
+	pr := model.PR{Number: 3}
+	m, cmd := m.startHooks(pr, t.TempDir(), "post_create", []string{"sleep 10", "echo never"})
+	if !m.cancelHooks(3) {
+		t.Fatal("cancelHooks() = false, want the running hooks cancelled")
+	}
+	_, done := drainHooks(t, cmd)
+	if done.failed != "sleep 10" || !errors.Is(done.err, context.Canceled) {
+		t.Errorf("done = %+v, want sleep 10 cancelled", done)
+	}
+	if m = m.finishHooks(done); m.hookStatus[3] != model.HookFailed || len(m.hookRuns) != 0 {
+		t.Errorf("after finishHooks: status = %v, cancels = %d", m.hookStatus[3], len(m.hookRuns))
+	}
+}
+
+func TestLogPanel_Append(t *testing.T) {
+	p := newLogPanel(40, 5)
+	for i := 0; i < maxLogLines+10; i++ {
+		p = p.Append("line")
+	}
+	if len(p.lines) != maxLogLines {
+		t.Errorf("lines = %d, want capped at %d", len(p.lines), maxLogLines)
+	}
+	if !p.viewport.AtBottom() {
+		t.Error("log panel does not follow new output")
+	}
+}
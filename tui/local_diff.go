@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kosuke9809/gh-review/git"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+// contextStep is how many context lines + and - add or remove.
+const contextStep = 3
+
+// localDiff is a PR's diff computed with git.
+type localDiff struct {
+	files []model.DiffFile
+	head  string
+	opts  git.DiffOptions
+}
+
+// localDiffMsg carries a PR's diff computed with git. diff.opts is set even
+// on failure. auto is set when the diff was computed on opening the PR rather
+// than on request.
+type localDiffMsg struct {
+	prNumber int
+	diff     localDiff
+	auto     bool
+	err      error
+}
+
+// localDiffCmd computes pr's diff with git. With fetch, missing commits are
+// fetched from origin first.
+func (m AppModel) localDiffCmd(pr model.PR, fetch, auto bool) tea.Cmd {
+	repoRoot, opts := m.repoRoot, m.diffOpts
+	return func() tea.Msg {
+		base, head, err := git.DiffRange(repoRoot, pr.Number, pr.BaseRef, pr.BaseSHA, pr.HeadSHA, fetch)
+		if err != nil {
+			return localDiffMsg{prNumber: pr.Number, diff: localDiff{opts: opts}, auto: auto, err: err}
+		}
+		fds, err := git.Diff(repoRoot, base, head, opts)
+		if err != nil {
+			return localDiffMsg{prNumber: pr.Number, diff: localDiff{opts: opts}, auto: auto, err: err}
+		}
+		files := make([]model.DiffFile, len(fds))
+		for i, f := range fds {
+			files[i] = model.DiffFile{
+				Filename:  f.Filename,
+				Previous:  f.Previous,
+				Patch:     f.Patch,
+				Additions: f.Additions,
+				Deletions: f.Deletions,
+			}
+		}
+		return localDiffMsg{prNumber: pr.Number, diff: localDiff{files: files, head: head, opts: opts}, auto: auto}
+	}
+}
+
+// applyLocalDiff shows a diff computed with git. Without local commits, an
+// automatic diff silently falls back to the API patches. Diffs computed with
+// options changed since are dropped; the diff with the current ones follows.
+func (m AppModel) applyLocalDiff(msg localDiffMsg) AppModel {
+	if msg.diff.opts != m.diffOpts {
+		return m
+	}
+	if msg.err != nil {
+		switch {
+		case !errors.Is(msg.err, git.ErrNoLocalObjects):
+			m.notice = fmt.Sprintf("#%d local diff: %v", msg.prNumber, msg.err)
+		case !msg.auto:
+			m.notice = fmt.Sprintf("#%d local diff: %v  [F]etch", msg.prNumber, msg.err)
+		}
+		return m
+	}
+	m.localDiffs[msg.prNumber] = msg.diff
+	return m.refreshDiff()
+}
+
+// refreshDiff shows the diff of the PR in the detail screen: the local diff
+// when there is one, and the API patches otherwise.
+func (m AppModel) refreshDiff() AppModel {
+	pr := m.selectedPR
+	if pr == nil {
+		return m
+	}
+	files, source, head := pr.DiffFiles, "", pr.HeadSHA
+	if d, ok := m.localDiffs[pr.Number]; ok {
+		files, source, head = d.files, localDiffSource(d, pr.HeadSHA, m.diffContext), d.head
+	}
+	m.diffTab.head, m.diffTab.base = head, m.mergeBases[mergeBaseKey(pr.BaseSHA, head)]
+	m.diffTab.syntax = m.highlighter
+	m.diffTab.split = m.diffSplit
+	m.diffTab.pager = ""
+	if !m.pagerOff {
+		m.diffTab.pager = m.pager
+	}
+	m.diffTab = m.diffTab.SetFiles(files).SetCoverage(m.profiles[pr.Number])
+	m.diffTab.source = source
+	return m
+}
+
+// localDiffSource describes a local diff for the file list title. Options
+// left at their defaults are not shown.
+func localDiffSource(d localDiff, headSHA string, context int) string {
+	s := "local @" + git.ShortSHA(d.head)
+	if headSHA != "" && d.head != headSHA {
+		s += " (older head)"
+	}
+	if d.opts.IgnoreWhitespace {
+		s += " -w"
+	}
+	if d.opts.Context == git.FullFile {
+		s += " full"
+	} else if d.opts.Context != context {
+		s += fmt.Sprintf(" U%d", d.opts.Context)
+	}
+	return s
+}
+
+// updateDiffOptions handles the keys that change how local diffs are
+// computed, and recomputes the diff of the PR in the detail screen.
+func (m AppModel) updateDiffOptions(key string) (AppModel, tea.Cmd) {
+	if m.selectedPR == nil {
+		return m, nil
+	}
+	switch key {
+	case "w":
+		m.diffOpts.IgnoreWhitespace = !m.diffOpts.IgnoreWhitespace
+	case "e":
+		if m.diffOpts.Context == git.FullFile {
+			m.diffOpts.Context = m.diffContext
+		} else {
+			m.diffOpts.Context = git.FullFile
+		}
+	case "+":
+		if m.diffOpts.Context != git.FullFile {
+			m.diffOpts.Context += contextStep
+		}
+	case "-":
+		if m.diffOpts.Context == git.FullFile {
+			m.diffOpts.Context = m.diffContext
+		}
+		m.diffOpts.Context = max(m.diffOpts.Context-contextStep, 0)
+	case "F":
+		return m, m.localDiffCmd(*m.selectedPR, true, false)
+	}
+	return m, m.localDiffCmd(*m.selectedPR, false, false)
+}
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kosuke9809/gh-review/coverage"
+	"github.com/kosuke9809/gh-review/git"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func diffModel() AppModel {
+	pr := model.PR{Number: 9, HeadSHA: "aaaaaaaaaa", DiffFiles: []model.DiffFile{{Filename: "api.go", Patch: "@@ -1 +1 @@\n+x"}}}
+	return AppModel{
+		selectedPR:  &pr,
+		profiles:    make(map[int]*coverage.Profile),
+		localDiffs:  make(map[int]localDiff),
+		diffContext: 3,
+		diffOpts:    git.DiffOptions{Context: 3},
+		diffTab:     newDiffTab(120, 40).SetFiles(pr.DiffFiles),
+	}
+}
+
+func TestApplyLocalDiff(t *testing.T) {
+	m := diffModel()
+	files := []model.DiffFile{{Filename: "big.go", Patch: "@@ -1 +1 @@\n+y"}, {Filename: "new.go", Previous: "old.go"}}
+	m = m.applyLocalDiff(localDiffMsg{prNumber: 9, diff: localDiff{files: files, head: "aaaaaaaaaa", opts: git.DiffOptions{Context: 3}}})
+	if len(m.diffTab.files) != 2 || m.diffTab.files[0].Filename != "big.go" {
+		t.Errorf("diff files = %+v, want the local diff", m.diffTab.files)
+	}
+	if m.diffTab.source != "local @aaaaaaa" {
+		t.Errorf("source = %q", m.diffTab.source)
+	}
+	if got := m.diffTab.fileItems()[1].(fileItem).Title(); got != "old.go → new.go" {
+		t.Errorf("renamed file title = %q", got)
+	}
+}
+
+func TestApplyLocalDiff_Fallback(t *testing.T) {
+	opts := localDiff{opts: git.DiffOptions{Context: 3}}
+	stale := localDiff{files: []model.DiffFile{{Filename: "big.go"}}, opts: git.DiffOptions{Context: 3, IgnoreWhitespace: true}}
+	tests := []struct {
+		name       string
+		msg        localDiffMsg
+		wantNotice bool
+	}{
+		{"自動ならAPIのパッチのまま", localDiffMsg{prNumber: 9, diff: opts, auto: true, err: git.ErrNoLocalObjects}, false},
+		{"要求されたら通知", localDiffMsg{prNumber: 9, diff: opts, err: git.ErrNoLocalObjects}, true},
+		{"git diffの失敗", localDiffMsg{prNumber: 9, diff: opts, auto: true, err: fmt.Errorf("git diff: exit status 128")}, true},
+		{"オプション変更前の差分は捨てる", localDiffMsg{prNumber: 9, diff: stale}, false},
+		{"オプション変更前の失敗も捨てる", localDiffMsg{prNumber: 9, diff: localDiff{opts: stale.opts}, err: git.ErrNoLocalObjects}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := diffModel().applyLocalDiff(tt.msg)
+			if (m.notice != "") != tt.wantNotice {
+				t.Errorf("notice = %q", m.notice)
+			}
+			if m.diffTab.files[0].Filename != "api.go" || m.diffTab.source != "" {
+				t.Errorf("diff = %+v from %q, want the API patches", m.diffTab.files, m.diffTab.source)
+			}
+		})
+	}
+}
+
+func TestLocalDiffSource(t *testing.T) {
+	tests := []struct {
+		name string
+		d    localDiff
+		want string
+	}{
+		{"既定の設定", localDiff{head: "aaaaaaaaaa", opts: git.DiffOptions{Context: 3}}, "local @aaaaaaa"},
+		{"古いhead", localDiff{head: "bbbbbbbbbb", opts: git.DiffOptions{Context: 3}}, "local @bbbbbbb (older head)"},
+		{"空白無視と全体表示", localDiff{head: "aaaaaaaaaa", opts: git.DiffOptions{Context: git.FullFile, IgnoreWhitespace: true}}, "local @aaaaaaa -w full"},
+		{"コンテキスト行数", localDiff{head: "aaaaaaaaaa", opts: git.DiffOptions{Context: 6}}, "local @aaaaaaa U6"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := localDiffSource(tt.d, "aaaaaaaaaa", 3); got != tt.want {
+				t.Errorf("localDiffSource() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxLogLines bounds the output kept by a log panel.
+const maxLogLines = 5000
+
+// logPanelModel is a scrollable pane of command output that follows new
+// lines while scrolled to the bottom.
+type logPanelModel struct {
+	viewport viewport.Model
+	lines    []string
+	width    int
+	height   int
+}
+
+func newLogPanel(width, height int) logPanelModel {
+	vp := viewport.New(width, height)
+	return logPanelModel{viewport: vp, width: width, height: height}
+}
+
+// Resize returns the panel with the given size, keeping its lines.
+func (m logPanelModel) Resize(width, height int) logPanelModel {
+	p := newLogPanel(width, height)
+	return p.Append(m.lines...)
+}
+
+// Append adds lines, dropping the oldest beyond maxLogLines.
+func (m logPanelModel) Append(lines ...string) logPanelModel {
+	follow := m.viewport.AtBottom() || len(m.lines) == 0
+	m.lines = append(m.lines, lines...)
+	if over := len(m.lines) - maxLogLines; over > 0 {
+		m.lines = append([]string(nil), m.lines[over:]...)
+	}
+	m.viewport.SetContent(strings.Join(m.lines, "\n"))
+	if follow {
+		m.viewport.GotoBottom()
+	}
+	return m
+}
+
+// Clear removes all lines.
+func (m logPanelModel) Clear() logPanelModel {
+	m.lines = nil
+	m.viewport.SetContent("")
+	return m
+}
+
+func (m logPanelModel) Update(msg tea.Msg) (logPanelModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m logPanelModel) View() string {
+	if len(m.lines) == 0 {
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height).
+			Align(lipgloss.Center, lipgloss.Center).
+			Render("No output yet")
+	}
+	return m.viewport.View()
+}
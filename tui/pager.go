@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kosuke9809/gh-review/git"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+// pagerTimeout bounds how long the pager may take to format one file.
+const pagerTimeout = 10 * time.Second
+
+// defaultDifftool shows the PR diff when no difftool is configured.
+const defaultDifftool = `git difftool --no-prompt "$BASE...$HEAD"`
+
+// pagedKey identifies the pager output of a patch at a width.
+type pagedKey struct {
+	name  string
+	patch string
+	width int
+}
+
+// pagedOutput is the result of running the pager on a patch. pending is set
+// while the pager runs.
+type pagedOutput struct {
+	out     string
+	err     error
+	pending bool
+}
+
+// pagedMsg carries the pager output of a patch.
+type pagedMsg struct {
+	key pagedKey
+	out string
+	err error
+}
+
+// difftoolMsg carries the commits to hand to the difftool.
+type difftoolMsg struct {
+	prNumber   int
+	base, head string
+	err        error
+}
+
+// difftoolDoneMsg reports that the difftool exited.
+type difftoolDoneMsg struct {
+	err error
+}
+
+// pagerInput is f's patch with the git headers pagers expect, so they can
+// show the file name and pick a syntax.
+func pagerInput(f model.DiffFile) string {
+	prev := f.Filename
+	if f.Previous != "" {
+		prev = f.Previous
+	}
+	return fmt.Sprintf("diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n%s\n", prev, f.Filename, prev, f.Filename, f.Patch)
+}
+
+// runPager runs the shell command script with input on stdin and returns its
+// output. COLUMNS is set to width.
+func runPager(script string, width int, input string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pagerTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", script)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("COLUMNS=%d", width))
+	cmd.Stdin = strings.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// togglePager switches the Diff tab between the configured pager and the
+// built-in colors.
+func (m AppModel) togglePager() (AppModel, tea.Cmd) {
+	if m.pager == "" {
+		m.notice = "no pager configured (diff.pager in config.yml)"
+		return m, nil
+	}
+	m.pagerOff = !m.pagerOff
+	// Retry files the pager failed on.
+	m.diffTab.paged = make(map[pagedKey]pagedOutput)
+	m.pagerFailed = false
+	m = m.refreshDiff()
+	return m, m.diffTab.PageCmd()
+}
+
+// applyPaged shows the pager output of a patch. The first failure since the
+// pager was last toggled is reported; the file keeps the built-in colors.
+func (m AppModel) applyPaged(msg pagedMsg) AppModel {
+	m.diffTab = m.diffTab.SetPaged(msg)
+	if msg.err != nil && !m.pagerFailed {
+		m.pagerFailed = true
+		m.notice = fmt.Sprintf("pager on %s: %v", msg.key.name, msg.err)
+	}
+	return m
+}
+
+// difftoolCmd finds the commits of pr's diff for the difftool.
+func (m AppModel) difftoolCmd(pr model.PR) tea.Cmd {
+	repoRoot := m.repoRoot
+	return func() tea.Msg {
+		base, head, err := git.DiffRange(repoRoot, pr.Number, pr.BaseRef, pr.BaseSHA, pr.HeadSHA, false)
+		return difftoolMsg{prNumber: pr.Number, base: base, head: head, err: err}
+	}
+}
+
+// openDifftool hands the terminal to the difftool until it exits.
+func (m AppModel) openDifftool(msg difftoolMsg) (AppModel, tea.Cmd) {
+	if msg.err != nil {
+		if errors.Is(msg.err, git.ErrNoLocalObjects) {
+			m.notice = fmt.Sprintf("#%d difftool: %v  [F]etch in the Diff tab", msg.prNumber, msg.err)
+		} else {
+			m.notice = fmt.Sprintf("#%d difftool: %v", msg.prNumber, msg.err)
+		}
+		return m, nil
+	}
+	script := m.difftool
+	if script == "" {
+		script = defaultDifftool
+	}
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Dir = m.repoRoot
+	cmd.Env = append(os.Environ(), "BASE="+msg.base, "HEAD="+msg.head)
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return difftoolDoneMsg{err: err}
+	})
+}
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestPagerInput(t *testing.T) {
+	got := pagerInput(model.DiffFile{Filename: "new.go", Previous: "old.go", Patch: "@@ -1 +1 @@\n-a\n+b"})
+	want := "diff --git a/old.go b/new.go\n--- a/old.go\n+++ b/new.go\n@@ -1 +1 @@\n-a\n+b\n"
+	if got != want {
+		t.Errorf("pagerInput() = %q, want %q", got, want)
+	}
+}
+
+func TestRunPager(t *testing.T) {
+	out, err := runPager(`echo "$COLUMNS"; grep '^+'`, 42, "@@ -1 +1 @@\n-a\n+b\n")
+	if err != nil {
+		t.Fatalf("runPager() error = %v", err)
+	}
+	if out != "42\n+b" {
+		t.Errorf("runPager() = %q", out)
+	}
+	if _, err := runPager("echo broken >&2; exit 2", 80, ""); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("runPager() error = %v, want stderr in the error", err)
+	}
+}
+
+func TestDiffTab_PageCmd(t *testing.T) {
+	tests := []struct {
+		name  string
+		pager string
+		want  string
+	}{
+		{"ページャの出力を表示", "tr a-z A-Z", "+HELLO"},
+		{"失敗したら組み込みの色", "exit 1", "+hello"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDiffTab(120, 40)
+			d.pager = tt.pager
+			d = d.SetFiles([]model.DiffFile{{Filename: "a.go", Patch: "@@ -1 +1 @@\n+hello"}})
+			cmd := d.PageCmd()
+			if cmd == nil {
+				t.Fatal("PageCmd() = nil, want the pager to run")
+			}
+			if d.PageCmd() != nil {
+				t.Error("PageCmd() ran the pager twice on the same patch")
+			}
+			d = d.SetPaged(cmd().(pagedMsg))
+			if got := ansi.Strip(d.diffView.View()); !strings.Contains(got, tt.want) {
+				t.Errorf("diff view = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPaged_ReportsFirstFailure(t *testing.T) {
+	m := AppModel{pager: "false", diffTab: newDiffTab(120, 40)}
+	m = m.applyPaged(pagedMsg{key: pagedKey{name: "a.go"}, err: errors.New("exit status 1")})
+	if !strings.Contains(m.notice, "a.go") {
+		t.Fatalf("notice = %q, want the first failure", m.notice)
+	}
+	m.notice = ""
+	if m = m.applyPaged(pagedMsg{key: pagedKey{name: "b.go"}, err: errors.New("exit status 1")}); m.notice != "" {
+		t.Errorf("notice = %q, want later failures left out", m.notice)
+	}
+	m, _ = m.togglePager()
+	if m = m.applyPaged(pagedMsg{key: pagedKey{name: "c.go"}, err: errors.New("exit status 1")}); !strings.Contains(m.notice, "c.go") {
+		t.Errorf("notice = %q, want a failure reported again after toggling", m.notice)
+	}
+}
@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,6 +14,7 @@ import (
 type prItem struct {
 	pr             model.PR
 	totalReviewers int
+	workDay        time.Duration
 }
 
 func (p prItem) Title() string       { return fmt.Sprintf("#%d  %s", p.pr.Number, p.pr.Title) }
@@ -32,6 +34,10 @@ func (p prItem) renderMeta() string {
 	wt := ""
 	if p.pr.HasWorktree {
 		wt = " " + lipgloss.NewStyle().Foreground(colorGreen).Render("⎇")
+		if p.pr.WorktreeOutdated() {
+			wt = " " + lipgloss.NewStyle().Foreground(colorYellow).Render("⎇↻")
+		}
+		wt += hookBadge(p.pr.Hooks)
 	}
 	author := ""
 	if p.pr.Author != "" {
@@ -41,7 +47,20 @@ func (p prItem) renderMeta() string {
 	if p.pr.BaseRef != "" {
 		branch = fmt.Sprintf("  %s←%s", p.pr.BaseRef, p.pr.HeadRef)
 	}
-	return fmt.Sprintf("%s%s  CI:%s  Review:%s  %s%s", author, branch, ci, review, badge, wt)
+	size := ""
+	if sz := p.pr.Size(); sz != "" {
+		size = "  " + sizeBadge(sz, p.pr.ReviewEstimate)
+	}
+	waiting := ""
+	if w := waitingStr(p.pr, p.workDay); w != "" {
+		waiting = "  " + w
+	}
+	snooze := ""
+	if p.pr.Snooze != nil {
+		snooze = "  " + lipgloss.NewStyle().Foreground(colorGray).Render("zz "+p.pr.Snooze.Label())
+	}
+	local := localRunBadge(p.pr)
+	return fmt.Sprintf("%s%s  CI:%s%s  Review:%s  %s%s%s%s%s", author, branch, ci, local, review, badge, size, waiting, wt, snooze)
 }
 
 // prItemDelegate colors PR title rows by ReviewState.
@@ -60,7 +79,7 @@ func (d prItemDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 		return
 	}
 	isSelected := index == m.Index()
-	title := rowStyleForState(pr.pr.ReviewState).Render(pr.Title())
+	title := rowStyleForPR(pr.pr).Render(pr.Title())
 	desc := lipgloss.NewStyle().Foreground(colorGray).Render(pr.Description())
 	if isSelected {
 		title = styleSelected.Render(pr.Title())
@@ -73,8 +92,9 @@ func (d prItemDelegate) Height() int  { return 2 }
 func (d prItemDelegate) Spacing() int { return 1 }
 
 // FormatPRRow returns a text representation of a PR row (used in tests).
-func FormatPRRow(pr model.PR, totalReviewers int, selected bool) string {
-	item := prItem{pr: pr, totalReviewers: totalReviewers}
+// Waiting time is shown in the working days of sched.
+func FormatPRRow(pr model.PR, totalReviewers int, sched model.WorkSchedule, selected bool) string {
+	item := prItem{pr: pr, totalReviewers: totalReviewers, workDay: sched.Day()}
 	base := item.Title() + "  " + item.renderMeta()
 	if selected {
 		return styleSelected.Render(base)
@@ -85,30 +105,45 @@ func FormatPRRow(pr model.PR, totalReviewers int, selected bool) string {
 type prsTabModel struct {
 	list   list.Model
 	prs    []model.PR
+	sched  model.WorkSchedule // to show waiting time in working days
 	width  int
 	height int
 }
 
-func newPRsTab(width, height int) prsTabModel {
+func newPRsTab(width, height int, sched model.WorkSchedule) prsTabModel {
 	delegate := newPRItemDelegate()
 	l := list.New(nil, delegate, width, height-4)
 	l.Title = ""
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(false)
 	l.SetShowHelp(false)
-	return prsTabModel{list: l, width: width, height: height}
+	return prsTabModel{list: l, sched: sched, width: width, height: height}
 }
 
+// SetPRs replaces the list contents. The selection follows the previously
+// selected PR when it is still present, even if the order changed; otherwise
+// the cursor stays at the same index, clamped to the new length.
 func (m prsTabModel) SetPRs(prs []model.PR) prsTabModel {
 	curIdx := m.list.Index()
+	curNum := 0
+	if pr := m.SelectedPR(); pr != nil {
+		curNum = pr.Number
+	}
 	m.prs = prs
 	items := make([]list.Item, len(prs))
+	sel := -1
 	for i, pr := range prs {
-		items[i] = prItem{pr: pr, totalReviewers: len(pr.Reviews) + 1}
+		items[i] = prItem{pr: pr, totalReviewers: len(pr.Reviews) + 1, workDay: m.sched.Day()}
+		if curNum != 0 && pr.Number == curNum {
+			sel = i
+		}
 	}
 	m.list.SetItems(items)
-	if curIdx > 0 && curIdx < len(items) {
-		m.list.Select(curIdx)
+	if sel < 0 {
+		sel = min(curIdx, len(items)-1)
+	}
+	if sel >= 0 {
+		m.list.Select(sel)
 	}
 	return m
 }
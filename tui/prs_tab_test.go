@@ -3,7 +3,9 @@ package tui_test
 import (
 	"strings"
 	"testing"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/kosuke9809/gh-review/model"
 	"github.com/kosuke9809/gh-review/tui"
@@ -14,6 +16,9 @@ func init() {
 	lipgloss.DefaultRenderer().SetColorProfile(termenv.TrueColor)
 }
 
+// sched has 9-hour working days.
+var sched = model.DefaultWorkSchedule()
+
 func TestFormatPRRow(t *testing.T) {
 	pr := model.PR{
 		Number:      142,
@@ -22,7 +27,7 @@ func TestFormatPRRow(t *testing.T) {
 		ReviewState: model.ReviewStateUpd,
 		HasWorktree: true,
 	}
-	row := tui.FormatPRRow(pr, 2, false)
+	row := tui.FormatPRRow(pr, 2, sched, false)
 	if row == "" {
 		t.Error("FormatPRRow returned empty string")
 	}
@@ -38,7 +43,7 @@ func TestRenderDetail(t *testing.T) {
 		Reviews:   []model.Review{{Author: "bob", State: "APPROVED"}},
 		Comments:  []model.Comment{{Author: "alice", Body: "LGTM", IsUnread: true}},
 	}
-	content := tui.RenderDetailContent(pr)
+	content := tui.RenderDetailContent(pr, sched)
 	if content == "" {
 		t.Error("RenderDetailContent returned empty string")
 	}
@@ -75,7 +80,7 @@ func TestFormatPRRow_ContainsAuthor(t *testing.T) {
 		CIStatus:    model.CIStatusPass,
 		ReviewState: model.ReviewStateNew,
 	}
-	row := tui.FormatPRRow(pr, 1, false)
+	row := tui.FormatPRRow(pr, 1, sched, false)
 	if !strings.Contains(row, "alice") {
 		t.Error("expected author in PR row")
 	}
@@ -91,7 +96,7 @@ func TestFormatPRRow_WorktreeIcon(t *testing.T) {
 		ReviewState: model.ReviewStateNew,
 		HasWorktree: true,
 	}
-	row := tui.FormatPRRow(pr, 1, false)
+	row := tui.FormatPRRow(pr, 1, sched, false)
 	if !strings.Contains(row, "⎇") {
 		t.Error("expected ⎇ worktree icon in PR row")
 	}
@@ -104,7 +109,7 @@ func TestRenderDetail_WithBody(t *testing.T) {
 		Author: "bob",
 		Body:   "This is the PR description.",
 	}
-	content := tui.RenderDetailContent(pr)
+	content := tui.RenderDetailContent(pr, sched)
 	if !strings.Contains(content, "Description") {
 		t.Error("expected Description section in detail content")
 	}
@@ -112,3 +117,125 @@ func TestRenderDetail_WithBody(t *testing.T) {
 		t.Error("expected body text in detail content")
 	}
 }
+
+func TestFormatPRRow_SnoozeBadge(t *testing.T) {
+	pr := model.PR{
+		Number: 12,
+		Title:  "Long-running RFC",
+		Snooze: &model.Snooze{Kind: model.SnoozeMute},
+	}
+	row := tui.FormatPRRow(pr, 1, sched, false)
+	if !strings.Contains(row, "muted") {
+		t.Error("expected snooze label in PR row")
+	}
+}
+
+func TestRenderDetail_PriorityBreakdown(t *testing.T) {
+	pr := model.PR{
+		Number: 7,
+		Title:  "Urgent fix",
+		Priority: model.PriorityScore{
+			Total:      25,
+			Components: []model.ScoreComponent{{Name: "Label", Detail: "urgent", Points: 15}, {Name: "Blocking", Detail: "only missing approval", Points: 10}},
+		},
+	}
+	content := tui.RenderDetailContent(pr, sched)
+	if !strings.Contains(content, "Priority 25.0") {
+		t.Error("expected priority total in detail content")
+	}
+	if !strings.Contains(content, "only missing approval") {
+		t.Error("expected priority breakdown in detail content")
+	}
+}
+
+func TestFormatPRRow_SizeBadge(t *testing.T) {
+	pr := model.PR{
+		Number:         13,
+		Title:          "Sized",
+		FilesLoaded:    true,
+		DiffFiles:      []model.DiffFile{{Filename: "main.go", Additions: 40, Deletions: 2}},
+		ReviewEstimate: 15 * time.Minute,
+	}
+	row := tui.FormatPRRow(pr, 1, sched, false)
+	if !strings.Contains(row, "[M ~15m]") {
+		t.Errorf("expected size badge with estimate in PR row, got %q", row)
+	}
+}
+
+func TestFormatPRRow_OverdueAge(t *testing.T) {
+	pr := model.PR{
+		Number:            14,
+		Title:             "Waiting",
+		IsReviewRequested: true,
+		SLA:               model.SLAOverdue,
+		Waiting:           26 * time.Hour,
+	}
+	row := tui.FormatPRRow(pr, 1, sched, false)
+	if !strings.Contains(row, "⏱ 2d") {
+		t.Errorf("expected waiting age in PR row, got %q", row)
+	}
+}
+
+func TestRenderStatsContent(t *testing.T) {
+	st := model.ReviewStats{
+		Since:              time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Reviewed:           4,
+		Approved:           3,
+		ChangesRequested:   1,
+		MedianFirstReview:  5 * time.Hour,
+		FirstReviewSamples: 4,
+		LinesPerWeek:       []model.WeekLines{{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Lines: 120}},
+		QueueDepth:         2,
+	}
+	content := tui.RenderStatsContent(st)
+	for _, want := range []string{"PRs reviewed                4", "5h (4 PRs)", "3 (75%)", "01-01", "120"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in stats content", want)
+		}
+	}
+}
+
+func TestRenderDetail_WorktreePushStatus(t *testing.T) {
+	pr := model.PR{
+		Number:         8,
+		Title:          "Fixup",
+		HasWorktree:    true,
+		WorktreePath:   "/repo/.worktrees/pr-8",
+		WorktreeBranch: &model.TrackingStatus{Branch: "fix", Upstream: "alice/fix", Ahead: 2},
+	}
+	content := tui.RenderDetailContent(pr, sched)
+	if !strings.Contains(content, "Branch fix → alice/fix") || !strings.Contains(content, "↑2 to push") {
+		t.Errorf("expected push status in detail content, got:\n%s", content)
+	}
+}
+
+func TestFormatPRRow_HookFailed(t *testing.T) {
+	pr := model.PR{Number: 13, Title: "Setup", HasWorktree: true, Hooks: model.HookFailed}
+	row := tui.FormatPRRow(pr, 1, sched, false)
+	if !strings.Contains(row, "hook✗") {
+		t.Error("expected failed hook badge in PR row")
+	}
+}
+
+func TestFormatPRRow_LocalRunBadge(t *testing.T) {
+	pr := model.PR{Number: 15, Title: "Checked", HeadSHA: "a", LocalRun: &model.LocalRun{Name: "test", ExitCode: 1, HeadSHA: "a"}}
+	row := tui.FormatPRRow(pr, 1, sched, false)
+	if !strings.Contains(row, "Local:") || !strings.Contains(row, "✗") {
+		t.Errorf("expected failed local-check badge in PR row, got %q", row)
+	}
+}
+
+func TestPRsTab_SetPRsKeepsSelectedPR(t *testing.T) {
+	m := tui.NewPRsTab(80, 40, sched).SetPRs([]model.PR{{Number: 1}, {Number: 2}, {Number: 3}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+	m = m.SetPRs([]model.PR{{Number: 3}, {Number: 1}, {Number: 2}})
+	if pr := m.SelectedPR(); pr == nil || pr.Number != 2 {
+		t.Fatalf("SelectedPR() = %v, want #2 after reorder", pr)
+	}
+
+	m = m.SetPRs([]model.PR{{Number: 3}})
+	if pr := m.SelectedPR(); pr == nil || pr.Number != 3 {
+		t.Fatalf("SelectedPR() = %v, want #3 after selected PR disappeared", pr)
+	}
+}
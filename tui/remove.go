@@ -0,0 +1,228 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kosuke9809/gh-review/git"
+)
+
+// undoWindow is how long a removed worktree can be restored with `u`.
+const undoWindow = 30 * time.Second
+
+// removeTarget is a worktree about to be removed, with the work it holds.
+type removeTarget struct {
+	prNumber int
+	path     string
+	head     string
+	changes  git.WorktreeChanges
+}
+
+// removeSave selects what to keep of a dirty worktree before removing it.
+type removeSave int
+
+const (
+	removeDiscard removeSave = iota
+	removeSavePatch
+	removeSaveRef
+)
+
+// removeConfirm is the pending confirmation for removing worktrees. Prune
+// always leaves dirty worktrees in place.
+type removeConfirm struct {
+	targets []removeTarget
+	prune   bool
+}
+
+func (c removeConfirm) dirty() int {
+	n := 0
+	for _, t := range c.targets {
+		if !t.changes.Clean() {
+			n++
+		}
+	}
+	return n
+}
+
+func (c removeConfirm) prompt() string {
+	dirty := c.dirty()
+	if c.prune {
+		return fmt.Sprintf("prune %d worktrees of closed PRs, skipping %d with changes? [y]es [n]o", len(c.targets)-dirty, dirty)
+	}
+	name := fmt.Sprintf("%d worktrees", len(c.targets))
+	if len(c.targets) == 1 {
+		name = fmt.Sprintf("worktree of #%d", c.targets[0].prNumber)
+	}
+	if dirty == 0 {
+		return "remove " + name + "? [y]es [n]o"
+	}
+	detail := fmt.Sprintf("%d with changes", dirty)
+	if len(c.targets) == 1 {
+		detail = c.targets[0].changes.String()
+	}
+	return fmt.Sprintf("remove %s (%s): [p]atch then remove [s]ave ref then remove [f]orce [n]o", name, detail)
+}
+
+// removalInspectedMsg carries the inspected targets for the confirmation.
+type removalInspectedMsg struct {
+	confirm removeConfirm
+	err     error
+}
+
+// worktreesRemovedMsg reports the outcome of removing worktrees.
+type worktreesRemovedMsg struct {
+	removed []removeTarget
+	saved   []string // patch files or refs holding saved changes
+	skipped int      // dirty worktrees left alone by prune
+	err     error
+}
+
+// worktreesRestoredMsg reports the outcome of undoing a removal.
+type worktreesRestoredMsg struct {
+	restored int
+	err      error
+}
+
+// removedWorktrees remembers the last removal for undo.
+type removedWorktrees struct {
+	targets []removeTarget
+	at      time.Time
+}
+
+// inspectRemovalCmd checks each worktree for work that removal would lose
+// before asking for confirmation.
+func (m AppModel) inspectRemovalCmd(targets []removeTarget, prune bool) tea.Cmd {
+	if len(targets) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		for i := range targets {
+			var err error
+			if targets[i].head, err = git.WorktreeHead(targets[i].path); err != nil {
+				return removalInspectedMsg{err: err}
+			}
+			if targets[i].changes, err = git.InspectWorktree(targets[i].path); err != nil {
+				return removalInspectedMsg{err: err}
+			}
+		}
+		return removalInspectedMsg{confirm: removeConfirm{targets: targets, prune: prune}}
+	}
+}
+
+// updateRemoveConfirm handles the key pressed while a removal awaits
+// confirmation.
+func (m AppModel) updateRemoveConfirm(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	c := *m.removeConfirm
+	dirty := c.dirty() > 0 && !c.prune
+	switch msg.String() {
+	case "y":
+		if dirty {
+			return m, nil
+		}
+	case "p", "s", "f":
+		if !dirty {
+			return m, nil
+		}
+	case "n", "esc":
+		m.removeConfirm = nil
+		return m, nil
+	default:
+		return m, nil
+	}
+	m.removeConfirm = nil
+	save := removeDiscard
+	switch msg.String() {
+	case "p":
+		save = removeSavePatch
+	case "s":
+		save = removeSaveRef
+	}
+	return m, m.removeWorktreesCmd(c, save)
+}
+
+// removeWorktreesCmd removes the confirmed worktrees, first saving the
+// changes of dirty ones as selected.
+func (m AppModel) removeWorktreesCmd(c removeConfirm, save removeSave) tea.Cmd {
+	repoRoot := m.repoRoot
+	return func() tea.Msg {
+		var msg worktreesRemovedMsg
+		var errs []error
+		now := time.Now()
+		for _, t := range c.targets {
+			if !t.changes.Clean() {
+				if c.prune {
+					msg.skipped++
+					continue
+				}
+				var saved string
+				var err error
+				switch save {
+				case removeSavePatch:
+					saved, err = git.SaveWorktreePatch(repoRoot, t.path, t.prNumber, now)
+				case removeSaveRef:
+					saved, err = git.SaveWorktreeRef(repoRoot, t.path, t.prNumber, now)
+				}
+				if err != nil {
+					// Never remove a worktree whose changes could not be saved.
+					errs = append(errs, err)
+					continue
+				}
+				if saved != "" {
+					msg.saved = append(msg.saved, saved)
+				}
+			}
+			if err := git.RemoveWorktreeAt(repoRoot, t.path); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			msg.removed = append(msg.removed, t)
+		}
+		msg.err = errors.Join(errs...)
+		return msg
+	}
+}
+
+// restoreWorktreesCmd recreates the worktrees of the last removal at the
+// commits they had checked out.
+func (m AppModel) restoreWorktreesCmd() tea.Cmd {
+	targets := m.lastRemoved.targets
+	repoRoot := m.repoRoot
+	return func() tea.Msg {
+		var msg worktreesRestoredMsg
+		var errs []error
+		for _, t := range targets {
+			if err := git.RestoreWorktree(repoRoot, t.path, t.head); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			msg.restored++
+		}
+		msg.err = errors.Join(errs...)
+		return msg
+	}
+}
+
+// canUndoRemove reports whether the last removal is still within the undo
+// window.
+func (m AppModel) canUndoRemove(now time.Time) bool {
+	return m.lastRemoved != nil && now.Sub(m.lastRemoved.at) < undoWindow
+}
+
+func removedNotice(msg worktreesRemovedMsg) string {
+	s := fmt.Sprintf("removed %d worktrees", len(msg.removed))
+	if len(msg.removed) == 1 {
+		s = fmt.Sprintf("removed worktree of #%d", msg.removed[0].prNumber)
+	}
+	if msg.skipped > 0 {
+		s += fmt.Sprintf(", skipped %d with changes", msg.skipped)
+	}
+	for _, saved := range msg.saved {
+		s += ", saved " + saved
+	}
+	if len(msg.removed) > 0 {
+		s += fmt.Sprintf("  [u]ndo within %ds", int(undoWindow.Seconds()))
+	}
+	return s
+}
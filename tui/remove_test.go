@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kosuke9809/gh-review/git"
+)
+
+func key(s string) tea.KeyMsg {
+	if s == "esc" {
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestUpdateRemoveConfirm(t *testing.T) {
+	dirty := removeTarget{prNumber: 3, path: "/wt/pr-3", changes: git.WorktreeChanges{Modified: 2}}
+	clean := removeTarget{prNumber: 4, path: "/wt/pr-4"}
+
+	tests := []struct {
+		name        string
+		confirm     removeConfirm
+		key         string
+		wantPending bool
+		wantCmd     bool
+	}{
+		{"変更なし: yで削除", removeConfirm{targets: []removeTarget{clean}}, "y", false, true},
+		{"変更あり: yでは削除しない", removeConfirm{targets: []removeTarget{dirty}}, "y", true, false},
+		{"変更あり: pでパッチ保存して削除", removeConfirm{targets: []removeTarget{dirty}}, "p", false, true},
+		{"変更あり: fで強制削除", removeConfirm{targets: []removeTarget{dirty}}, "f", false, true},
+		{"変更あり: nでキャンセル", removeConfirm{targets: []removeTarget{dirty}}, "n", false, false},
+		{"prune: 変更ありでもyで確定", removeConfirm{targets: []removeTarget{dirty, clean}, prune: true}, "y", false, true},
+		{"Escでキャンセル", removeConfirm{targets: []removeTarget{clean}}, "esc", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.confirm
+			m := AppModel{removeConfirm: &c}
+			m, cmd := m.updateRemoveConfirm(key(tt.key))
+			if (m.removeConfirm != nil) != tt.wantPending {
+				t.Errorf("pending = %v, want %v", m.removeConfirm != nil, tt.wantPending)
+			}
+			if (cmd != nil) != tt.wantCmd {
+				t.Errorf("cmd = %v, want cmd %v", cmd != nil, tt.wantCmd)
+			}
+		})
+	}
+}
+
+func TestRemoveConfirm_Prompt(t *testing.T) {
+	c := removeConfirm{targets: []removeTarget{{prNumber: 3, changes: git.WorktreeChanges{Untracked: 1, Unreferenced: 2}}}}
+	got := c.prompt()
+	if !strings.Contains(got, "#3") || !strings.Contains(got, "1 untracked, 2 unreferenced commits") || !strings.Contains(got, "[p]atch") {
+		t.Errorf("prompt() = %q", got)
+	}
+}
+
+func TestCanUndoRemove(t *testing.T) {
+	now := time.Now()
+	m := AppModel{lastRemoved: &removedWorktrees{at: now}}
+	if !m.canUndoRemove(now.Add(undoWindow - time.Second)) {
+		t.Error("canUndoRemove() = false within the window")
+	}
+	if m.canUndoRemove(now.Add(undoWindow)) {
+		t.Error("canUndoRemove() = true after the window")
+	}
+}
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/kosuke9809/gh-review/model"
+	"github.com/kosuke9809/gh-review/state"
+)
+
+// maxDetailVisit bounds a single visit to the detail screen, so a terminal
+// left open overnight does not skew the review-time history.
+const maxDetailVisit = 2 * time.Hour
+
+// leaveDetail adds the time spent on the selected PR's detail screen to its
+// pending review time. It reports whether the state changed.
+func (m AppModel) leaveDetail(now time.Time) (AppModel, bool) {
+	if m.selectedPR == nil || m.detailOpenedAt.IsZero() {
+		return m, false
+	}
+	opened := m.detailOpenedAt
+	spent := min(now.Sub(opened), maxDetailVisit)
+	m.detailOpenedAt = time.Time{}
+	if spent <= 0 {
+		return m, false
+	}
+	key := model.PRKey(m.repoName, m.selectedPR.Number)
+	if m.state.ReviewPending == nil {
+		m.state.ReviewPending = make(map[string]state.PendingReview)
+	}
+	p, ok := m.state.ReviewPending[key]
+	if !ok {
+		p.Since = opened
+	}
+	p.Spent += spent
+	m.state.ReviewPending[key] = p
+	return m, true
+}
+
+// recordReview moves pending review time into the history once my review
+// submitted after the first visit appears. It reports whether the state changed.
+func (m AppModel) recordReview(pr model.PR) (AppModel, bool) {
+	key := model.PRKey(m.repoName, pr.Number)
+	p, ok := m.state.ReviewPending[key]
+	if !ok {
+		return m, false
+	}
+	for _, r := range pr.Reviews {
+		if r.Author != m.currentUser || r.CreatedAt.Before(p.Since) {
+			continue
+		}
+		delete(m.state.ReviewPending, key)
+		m.state.AddReview(model.ReviewRecord{
+			Repo:       m.repoName,
+			Number:     pr.Number,
+			Lines:      pr.ReviewableLines(),
+			Duration:   p.Spent,
+			ReviewedAt: r.CreatedAt,
+		})
+		return m, true
+	}
+	return m, false
+}
+
+// prunePending drops from pending the review time of this repository's PRs
+// that are no longer open.
+func (m AppModel) prunePending(pending map[string]state.PendingReview) {
+	open := make(map[string]bool, len(m.allPRs))
+	for _, pr := range m.allPRs {
+		open[model.PRKey(m.repoName, pr.Number)] = true
+	}
+	for key := range pending {
+		if model.PRKeyRepo(key) == m.repoName && !open[key] {
+			delete(pending, key)
+		}
+	}
+}
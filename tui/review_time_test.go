@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/model"
+	"github.com/kosuke9809/gh-review/state"
+)
+
+func TestRecordReview_AfterDetailVisit(t *testing.T) {
+	opened := time.Now().Add(-30 * time.Minute)
+	pr := model.PR{Number: 4, DetailLoaded: true, FilesLoaded: true, DiffFiles: []model.DiffFile{{Filename: "a.go", Additions: 12}}}
+	m := AppModel{repoName: "o/r", currentUser: "me", selectedPR: &pr, detailOpenedAt: opened}
+
+	m, changed := m.leaveDetail(opened.Add(20 * time.Minute))
+	if !changed {
+		t.Fatal("leaveDetail() changed = false, want true")
+	}
+
+	pr.Reviews = []model.Review{
+		{Author: "me", State: "APPROVED", CreatedAt: opened.Add(-time.Hour)},
+		{Author: "me", State: "APPROVED", CreatedAt: opened.Add(25 * time.Minute)},
+	}
+	m, changed = m.recordReview(pr)
+	if !changed {
+		t.Fatal("recordReview() changed = false, want true")
+	}
+	if len(m.state.ReviewHistory) != 1 {
+		t.Fatalf("ReviewHistory = %+v, want 1 record", m.state.ReviewHistory)
+	}
+	if r := m.state.ReviewHistory[0]; r.Duration != 20*time.Minute || r.Lines != 12 {
+		t.Errorf("record = %+v, want 20m over 12 lines", r)
+	}
+	if len(m.state.ReviewPending) != 0 {
+		t.Errorf("ReviewPending = %v, want empty", m.state.ReviewPending)
+	}
+}
+
+func TestPrunePending(t *testing.T) {
+	m := AppModel{repoName: "o/r", allPRs: []model.PR{{Number: 1}}}
+	pending := map[string]state.PendingReview{"o/r#1": {}, "o/r#2": {}, "o/other#2": {}}
+
+	m.prunePending(pending)
+	if _, ok := pending["o/r#2"]; ok || len(pending) != 2 {
+		t.Errorf("pending = %v, want o/r#1 and o/other#2 kept", pending)
+	}
+}
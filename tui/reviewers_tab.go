@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+type reviewerItem struct {
+	load model.ReviewerLoad
+}
+
+func (r reviewerItem) Title() string { return r.load.Label() }
+func (r reviewerItem) Description() string {
+	oldest := "-"
+	if !r.load.OldestSince.IsZero() {
+		oldest = formatDuration(time.Since(r.load.OldestSince))
+	}
+	return fmt.Sprintf("%d requested  oldest:%s  blocking:%d", r.load.Requested, oldest, r.load.ChangesRequested)
+}
+func (r reviewerItem) FilterValue() string { return r.load.Name }
+
+type reviewerItemDelegate struct {
+	list.DefaultDelegate
+}
+
+func (d reviewerItemDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	r, ok := item.(reviewerItem)
+	if !ok {
+		d.DefaultDelegate.Render(w, m, index, item)
+		return
+	}
+	title := lipgloss.NewStyle().Foreground(colorCyan).Render(r.Title())
+	desc := lipgloss.NewStyle().Foreground(colorGray).Render(r.Description())
+	if r.load.ChangesRequested > 0 {
+		desc += "  " + styleCIFail.Render("✗")
+	}
+	if index == m.Index() {
+		title = styleSelected.Render(r.Title())
+		desc = styleSelected.Foreground(colorGray).Render(r.Description())
+	}
+	fmt.Fprintf(w, "%s\n%s\n", title, desc)
+}
+
+func (d reviewerItemDelegate) Height() int  { return 2 }
+func (d reviewerItemDelegate) Spacing() int { return 1 }
+
+type reviewersTabModel struct {
+	list   list.Model
+	loads  []model.ReviewerLoad
+	width  int
+	height int
+}
+
+func newReviewersTab(width, height int) reviewersTabModel {
+	l := list.New(nil, reviewerItemDelegate{DefaultDelegate: list.NewDefaultDelegate()}, width, height-4)
+	l.Title = ""
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	return reviewersTabModel{list: l, width: width, height: height}
+}
+
+func (m reviewersTabModel) SetLoads(loads []model.ReviewerLoad) reviewersTabModel {
+	m.loads = loads
+	items := make([]list.Item, len(loads))
+	for i, l := range loads {
+		items[i] = reviewerItem{load: l}
+	}
+	m.list.SetItems(items)
+	return m
+}
+
+func (m reviewersTabModel) Selected() *model.ReviewerLoad {
+	if item, ok := m.list.SelectedItem().(reviewerItem); ok {
+		return &item.load
+	}
+	return nil
+}
+
+func (m reviewersTabModel) Update(msg tea.Msg) (reviewersTabModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m reviewersTabModel) View() string {
+	if len(m.loads) == 0 {
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height-4).
+			Align(lipgloss.Center, lipgloss.Center).
+			Render("No outstanding review requests")
+	}
+	return m.list.View()
+}
+
+// prReviewers is what the Reviewers screen fetched about a PR. It is kept
+// apart from the PR, which each refresh replaces.
+type prReviewers struct {
+	reviews  []model.Review // nil when the PR's details were loaded instead
+	requests map[string]time.Time
+}
+
+// withReviewers fills in pr's reviews and review requests fetched for the
+// Reviewers screen.
+func (m AppModel) withReviewers(pr model.PR) model.PR {
+	r, ok := m.reviewers[pr.Number]
+	if !ok {
+		return pr
+	}
+	if !pr.DetailLoaded {
+		pr.Reviews = r.reviews
+	}
+	pr.ReviewRequests = r.requests
+	return pr
+}
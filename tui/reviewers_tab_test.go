@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestWithReviewers(t *testing.T) {
+	at := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	reviews := []model.Review{{Author: "carol", State: "CHANGES_REQUESTED"}}
+	m := AppModel{reviewers: map[int]prReviewers{
+		1: {reviews: reviews, requests: map[string]time.Time{"alice": at}},
+		2: {requests: map[string]time.Time{"alice": at}},
+	}}
+
+	// A refresh replaces the PRs; what was fetched for the screen still applies.
+	pr := m.withReviewers(model.PR{Number: 1})
+	if len(pr.Reviews) != 1 || !pr.ReviewRequests["alice"].Equal(at) {
+		t.Errorf("PR #1 = %+v, want fetched reviews and requests", pr)
+	}
+	if !pr.InvolvesReviewer("carol", false) {
+		t.Error("PR #1 not involving carol, who requested changes")
+	}
+
+	// Loaded details win over the reviews fetched for the screen.
+	own := []model.Review{{Author: "bob", State: "APPROVED"}}
+	pr = m.withReviewers(model.PR{Number: 2, DetailLoaded: true, Reviews: own})
+	if len(pr.Reviews) != 1 || pr.Reviews[0].Author != "bob" {
+		t.Errorf("PR #2 reviews = %+v, want the loaded ones", pr.Reviews)
+	}
+	if pr := m.withReviewers(model.PR{Number: 3}); pr.ReviewRequests != nil {
+		t.Errorf("PR #3 = %+v, want nothing fetched", pr)
+	}
+}
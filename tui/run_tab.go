@@ -0,0 +1,256 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kosuke9809/gh-review/config"
+	"github.com/kosuke9809/gh-review/model"
+	"github.com/kosuke9809/gh-review/runner"
+)
+
+// runPreset is a named command from the repository's config.
+type runPreset struct {
+	name   string
+	script string
+}
+
+func runPresets(r config.Repo) []runPreset {
+	var presets []runPreset
+	for _, name := range r.CommandNames() {
+		presets = append(presets, runPreset{name: name, script: r.Commands[name]})
+	}
+	return presets
+}
+
+// runOutputMsg is a line printed by the command running on the Run screen.
+// ch delivers the command's next message.
+type runOutputMsg struct {
+	line string
+	ch   <-chan tea.Msg
+}
+
+// runDoneMsg reports that the command running on the Run screen exited.
+type runDoneMsg struct {
+	prNumber int
+	name     string
+	head     string // commit checked out when the command started
+	code     int
+	err      error
+}
+
+// runTabModel is the Run screen: it runs one command at a time in a PR's
+// worktree and shows its output.
+type runTabModel struct {
+	prNumber int
+	path     string
+	presets  []runPreset
+	output   logPanelModel
+	input    textinput.Model
+	editing  bool   // the ad-hoc command prompt is open
+	running  string // name of the running command; empty when idle
+	cancel   context.CancelFunc
+	width    int
+	height   int
+}
+
+func newRunTab(width, height int, presets []runPreset) runTabModel {
+	return runTabModel{
+		presets: presets,
+		output:  newLogPanel(width, height-2),
+		width:   width,
+		height:  height,
+	}
+}
+
+// Resize returns the tab with the given size, keeping its state.
+func (m runTabModel) Resize(width, height int) runTabModel {
+	m.width, m.height = width, height
+	m.output = m.output.Resize(width, height-2)
+	return m
+}
+
+// Open points the tab at pr's worktree. The output of another PR is cleared.
+func (m runTabModel) Open(pr model.PR) runTabModel {
+	if pr.Number != m.prNumber {
+		m.output = m.output.Clear()
+	}
+	m.prNumber, m.path = pr.Number, pr.WorktreePath
+	return m
+}
+
+// Preset returns the preset bound to key "1".."9".
+func (m runTabModel) Preset(key string) (runPreset, bool) {
+	if len(key) != 1 || key[0] < '1' || key[0] > '9' {
+		return runPreset{}, false
+	}
+	i := int(key[0] - '1')
+	if i >= len(m.presets) {
+		return runPreset{}, false
+	}
+	return m.presets[i], true
+}
+
+// Cancel stops the running command, if any.
+func (m runTabModel) Cancel() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+}
+
+func (m runTabModel) Update(msg tea.Msg) (runTabModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.output, cmd = m.output.Update(msg)
+	return m, cmd
+}
+
+func (m runTabModel) View() string {
+	var keys []string
+	for i, p := range m.presets {
+		if i == 9 {
+			break
+		}
+		keys = append(keys, fmt.Sprintf("[%d]%s", i+1, p.name))
+	}
+	keys = append(keys, "[!]command")
+	header := lipgloss.NewStyle().Foreground(colorGray).Render(m.path) + "\n" + strings.Join(keys, " ")
+	if m.editing {
+		header = lipgloss.NewStyle().Foreground(colorGray).Render(m.path) + "\n" + m.input.View()
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, m.output.View())
+}
+
+func newCommandInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "$ "
+	ti.Placeholder = "command"
+	return ti
+}
+
+// openRun shows the Run screen for the selected PR, or for the PR shown in
+// the detail screen.
+func (m AppModel) openRun() AppModel {
+	pr := m.prsTab.SelectedPR()
+	if m.screen == screenDetail {
+		pr = m.selectedPR
+	}
+	if pr == nil {
+		return m
+	}
+	if !pr.HasWorktree {
+		m.notice = fmt.Sprintf("#%d has no worktree  [w]orktree", pr.Number)
+		return m
+	}
+	if m.runTab.running != "" && m.runTab.prNumber != pr.Number {
+		m.notice = fmt.Sprintf("#%d is running %s", m.runTab.prNumber, m.runTab.running)
+		return m
+	}
+	m.runTab = m.runTab.Open(*pr)
+	m.runFrom = m.screen
+	m.screen = screenRun
+	return m
+}
+
+// updateCommandInput handles keys while the ad-hoc command prompt is open.
+func (m AppModel) updateCommandInput(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.runTab.editing = false
+		return m, nil
+	case "enter":
+		m.runTab.editing = false
+		script := strings.TrimSpace(m.runTab.input.Value())
+		if script == "" {
+			return m, nil
+		}
+		return m.startRun(script, script)
+	}
+	var cmd tea.Cmd
+	m.runTab.input, cmd = m.runTab.input.Update(msg)
+	return m, cmd
+}
+
+// startRun runs script in the Run screen's worktree, streaming its output.
+func (m AppModel) startRun(name, script string) (AppModel, tea.Cmd) {
+	if m.runTab.running != "" {
+		m.notice = fmt.Sprintf("%s is still running  [x]cancel", m.runTab.running)
+		return m, nil
+	}
+	pr, ok := m.prByNumber(m.runTab.prNumber)
+	if !ok {
+		return m, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.runTab.running, m.runTab.cancel = name, cancel
+	m.runTab.output = m.runTab.output.Append("$ " + script)
+	m = m.applyFilter()
+
+	ch := make(chan tea.Msg, 64)
+	c := runner.Command{Dir: m.runTab.path, Env: prEnv(pr, m.runTab.path, m.repoRoot), Script: script}
+	head := pr.WorktreeHEAD
+	go func() {
+		defer close(ch)
+		defer cancel()
+		code, err := runner.Run(ctx, c, func(line string) { ch <- runOutputMsg{line: line, ch: ch} })
+		ch <- runDoneMsg{prNumber: pr.Number, name: name, head: head, code: code, err: err}
+	}()
+	return m, waitForMsg(ch)
+}
+
+// finishRun records the outcome of the Run screen's command.
+func (m AppModel) finishRun(msg runDoneMsg) (AppModel, tea.Cmd) {
+	m.runTab.running, m.runTab.cancel = "", nil
+	var line string
+	switch {
+	case errors.Is(msg.err, context.Canceled):
+		line = msg.name + " cancelled"
+	case msg.err != nil:
+		line = fmt.Sprintf("%s: %v", msg.name, msg.err)
+	case msg.code == 0:
+		line = msg.name + " passed"
+	default:
+		line = fmt.Sprintf("%s failed (exit %d)", msg.name, msg.code)
+	}
+	m.runTab.output = m.runTab.output.Append(line)
+	if m.screen != screenRun {
+		m.notice = fmt.Sprintf("#%d %s", msg.prNumber, line)
+	}
+	if msg.err != nil {
+		// A cancelled or unstartable command has no exit status to keep.
+		m = m.applyFilter()
+		return m, nil
+	}
+	return m.recordLocalRun(model.LocalRun{Name: msg.name, ExitCode: msg.code, HeadSHA: msg.head}, msg.prNumber)
+}
+
+// recordLocalRun keeps r as the last command run in the PR's worktree.
+func (m AppModel) recordLocalRun(r model.LocalRun, prNumber int) (AppModel, tea.Cmd) {
+	r.At = time.Now()
+	if m.state.LocalRuns == nil {
+		m.state.LocalRuns = make(map[string]model.LocalRun)
+	}
+	m.state.LocalRuns[model.PRKey(m.repoName, prNumber)] = r
+	m = m.applyFilter()
+	return m, m.saveStateCmd()
+}
+
+// localRun returns the command running in pr's worktree, or the outcome of
+// the last one.
+func (m AppModel) localRun(pr model.PR) *model.LocalRun {
+	if m.runTab.running != "" && m.runTab.prNumber == pr.Number {
+		return &model.LocalRun{Name: m.runTab.running, Running: true}
+	}
+	if m.testsTab.running && m.testsTab.prNumber == pr.Number {
+		return &model.LocalRun{Name: goTestRunName, Running: true}
+	}
+	if r, ok := m.state.LocalRuns[model.PRKey(m.repoName, pr.Number)]; ok {
+		return &r
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+// drainRun delivers the messages of the command started by cmd to m until it
+// exits.
+func drainRun(t *testing.T, m AppModel, cmd tea.Cmd) AppModel {
+	t.Helper()
+	for cmd != nil {
+		switch msg := cmd().(type) {
+		case runOutputMsg:
+			m.runTab.output = m.runTab.output.Append(msg.line)
+			cmd = waitForMsg(msg.ch)
+		case runDoneMsg:
+			m, _ = m.finishRun(msg)
+			return m
+		default:
+			t.Fatalf("unexpected message %T", msg)
+		}
+	}
+	t.Fatal("command did not finish")
+	return m
+}
+
+func runModel(t *testing.T) AppModel {
+	pr := model.PR{Number: 3, HasWorktree: true, WorktreePath: t.TempDir(), WorktreeHEAD: "abc", HeadSHA: "abc"}
+	m := AppModel{repoName: "octo/app", allPRs: []model.PR{pr}, prsTab: newPRsTab(80, 20, model.WorkSchedule{}), runTab: newRunTab(80, 20, nil)}
+	m.runTab = m.runTab.Open(pr)
+	return m
+}
+
+func TestStartRun_RecordsExitCode(t *testing.T) {
+	m, cmd := runModel(t).startRun("test", `echo "pr $PR_NUMBER"; exit 3`)
+	if r := m.localRun(m.allPRs[0]); r == nil || !r.Running {
+		t.Fatalf("localRun() = %+v while running, want running", r)
+	}
+	m = drainRun(t, m, cmd)
+	want := []string{`$ echo "pr $PR_NUMBER"; exit 3`, "pr 3", "test failed (exit 3)"}
+	if len(m.runTab.output.lines) != len(want) {
+		t.Fatalf("output = %q, want %q", m.runTab.output.lines, want)
+	}
+	for i := range want {
+		if m.runTab.output.lines[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, m.runTab.output.lines[i], want[i])
+		}
+	}
+	r := m.state.LocalRuns["octo/app#3"]
+	if r.Name != "test" || r.ExitCode != 3 || r.HeadSHA != "abc" {
+		t.Errorf("LocalRuns[octo/app#3] = %+v, want test exit 3 at abc", r)
+	}
+	if m.runTab.running != "" {
+		t.Error("Run screen still running after the command exited")
+	}
+}
+
+func TestStartRun_Cancel(t *testing.T) {
+	m, cmd := runModel(t).startRun("serve", "sleep 10")
+	m.runTab.Cancel()
+	m = drainRun(t, m, cmd)
+	if _, ok := m.state.LocalRuns["octo/app#3"]; ok {
+		t.Error("cancelled command was recorded")
+	}
+	if got := m.runTab.output.lines[len(m.runTab.output.lines)-1]; got != "serve cancelled" {
+		t.Errorf("last line = %q, want serve cancelled", got)
+	}
+}
+
+func TestRunTab_Preset(t *testing.T) {
+	tab := newRunTab(80, 20, []runPreset{{"build", "go build ./..."}, {"test", "go test ./..."}})
+	tests := []struct {
+		key  string
+		want string
+		ok   bool
+	}{
+		{"1", "build", true},
+		{"2", "test", true},
+		{"3", "", false},
+		{"x", "", false},
+	}
+	for _, tt := range tests {
+		p, ok := tab.Preset(tt.key)
+		if ok != tt.ok || p.name != tt.want {
+			t.Errorf("Preset(%q) = %q, %v; want %q, %v", tt.key, p.name, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func newDateInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Snooze until: "
+	ti.Placeholder = "YYYY-MM-DD"
+	ti.CharLimit = 10
+	return ti
+}
+
+// updateSnoozeMenu handles the key pressed after `z`.
+func (m AppModel) updateSnoozeMenu(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	m.snoozeMenu = false
+	now := time.Now()
+	switch msg.String() {
+	case "1", "3", "7":
+		days := int(msg.Runes[0] - '0')
+		return m.snoozeSelected(model.Snooze{Kind: model.SnoozeUntil, Until: startOfDay(now).AddDate(0, 0, days)})
+	case "d":
+		m.dateInputActive = true
+		m.dateInput = newDateInput()
+		return m, m.dateInput.Focus()
+	case "c":
+		return m.snoozeSelected(model.Snooze{Kind: model.SnoozeCommits})
+	case "m":
+		return m.snoozeSelected(model.Snooze{Kind: model.SnoozeMention})
+	case "x":
+		return m.snoozeSelected(model.Snooze{Kind: model.SnoozeMute})
+	case "u":
+		return m.unsnoozeSelected()
+	}
+	return m, nil
+}
+
+// updateDateInput handles keys while the snooze date prompt is open.
+func (m AppModel) updateDateInput(msg tea.KeyMsg) (AppModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.dateInputActive = false
+		return m, nil
+	case "enter":
+		until, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(m.dateInput.Value()), time.Local)
+		if err != nil {
+			m.err = fmt.Errorf("snooze: invalid date %q", m.dateInput.Value())
+			return m, nil
+		}
+		m.dateInputActive = false
+		m.err = nil
+		return m.snoozeSelected(model.Snooze{Kind: model.SnoozeUntil, Until: until})
+	}
+	var cmd tea.Cmd
+	m.dateInput, cmd = m.dateInput.Update(msg)
+	return m, cmd
+}
+
+func (m AppModel) snoozeSelected(s model.Snooze) (AppModel, tea.Cmd) {
+	pr := m.prsTab.SelectedPR()
+	if pr == nil {
+		return m, nil
+	}
+	s.Since = time.Now()
+	s.HeadSHA = pr.HeadSHA
+	if m.state.Snoozes == nil {
+		m.state.Snoozes = make(map[string]model.Snooze)
+	}
+	m.state.Snoozes[model.SnoozeKey(m.repoName, pr.Number)] = s
+	m = m.applyFilter()
+	return m, m.saveStateCmd()
+}
+
+func (m AppModel) unsnoozeSelected() (AppModel, tea.Cmd) {
+	pr := m.prsTab.SelectedPR()
+	if pr == nil || pr.Snooze == nil {
+		return m, nil
+	}
+	delete(m.state.Snoozes, model.SnoozeKey(m.repoName, pr.Number))
+	m = m.applyFilter()
+	return m, m.saveStateCmd()
+}
+
+// mentionSnoozes returns, for each PR snoozed until a mention, the time the
+// snooze started.
+func (m AppModel) mentionSnoozes() map[int]time.Time {
+	result := make(map[int]time.Time)
+	for _, pr := range m.allPRs {
+		if s, ok := m.state.Snoozes[model.SnoozeKey(m.repoName, pr.Number)]; ok && s.Kind == model.SnoozeMention {
+			result[pr.Number] = s.Since
+		}
+	}
+	return result
+}
+
+// expireSnoozes drops snoozes whose condition fired on this refresh and
+// those for PRs that are no longer open. It reports whether anything changed.
+func (m AppModel) expireSnoozes(mentioned map[int]bool) (AppModel, bool) {
+	if len(m.state.Snoozes) == 0 {
+		return m, false
+	}
+	now := time.Now()
+	open := make(map[string]bool, len(m.allPRs))
+	changed := false
+	for _, pr := range m.allPRs {
+		key := model.SnoozeKey(m.repoName, pr.Number)
+		open[key] = true
+		if s, ok := m.state.Snoozes[key]; ok && s.Expired(pr, mentioned[pr.Number], now) {
+			delete(m.state.Snoozes, key)
+			changed = true
+		}
+	}
+	prefix := m.repoName + "#"
+	for key := range m.state.Snoozes {
+		if strings.HasPrefix(key, prefix) && !open[key] {
+			delete(m.state.Snoozes, key)
+			changed = true
+		}
+	}
+	return m, changed
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, t.Location())
+}
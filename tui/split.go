@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kosuke9809/gh-review/coverage"
+	"github.com/kosuke9809/gh-review/model"
+	"github.com/kosuke9809/gh-review/patch"
+)
+
+// minSplitWidth is the narrowest diff viewport that shows the split view;
+// narrower ones fall back to the unified view.
+const minSplitWidth = 100
+
+// tabWidth is the distance between tab stops in the split view.
+const tabWidth = 4
+
+var styleLineNum = lipgloss.NewStyle().Foreground(colorGray)
+
+// splitActive reports whether the diff is shown side by side.
+func (m diffTabModel) splitActive() bool {
+	return m.split && m.diffView.Width >= minSplitWidth
+}
+
+// renderSplit renders f's patch in aligned old and new columns, each with
+// its line numbers. The coverage gutter goes on the new side. Tabs are
+// expanded first, as the terminal would otherwise move them to its own tab
+// stops past the column.
+func (m diffTabModel) renderSplit(f model.DiffFile) string {
+	f.Patch = expandTabs(f.Patch)
+	hunks := patch.Parse(f.Patch)
+	colored := m.colorPatch(f)
+	var marks []coverage.Mark
+	if m.profile != nil {
+		marks = coverage.Annotate(f.Patch, m.profile.Lines(f.Filename))
+	}
+	numW := numberWidth(hunks)
+	colW := (m.diffView.Width - 1) / 2
+	sep := styleLineNum.Render("│")
+	var rows []string
+	for _, h := range hunks {
+		rows = append(rows, styleDiffHdr.Render(ansi.Truncate(h.Lines[0].Text, m.diffView.Width, "…")))
+		for _, r := range patch.Split(h) {
+			left := splitCell(r.Old, oldNum(r.Old), " ", colored, colW, numW)
+			gutter := " "
+			if r.New != nil && marks != nil {
+				gutter = coverageGutter(marks[r.New.Index])
+			}
+			right := splitCell(r.New, newNum(r.New), gutter, colored, colW, numW)
+			rows = append(rows, left+sep+right)
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// expandTabs replaces the tabs in the text of each patch line with spaces up
+// to the next tab stop.
+func expandTabs(p string) string {
+	if !strings.Contains(p, "\t") {
+		return p
+	}
+	lines := strings.Split(p, "\n")
+	for i, l := range lines {
+		if l == "" || strings.HasPrefix(l, "@@") || !strings.Contains(l, "\t") {
+			continue
+		}
+		var b strings.Builder
+		b.WriteByte(l[0])
+		col := 0
+		for _, r := range l[1:] {
+			if r == '\t' {
+				n := tabWidth - col%tabWidth
+				b.WriteString(strings.Repeat(" ", n))
+				col += n
+				continue
+			}
+			b.WriteRune(r)
+			col += ansi.StringWidth(string(r))
+		}
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// numberWidth returns the width of the largest line number in hunks.
+func numberWidth(hunks []patch.Hunk) int {
+	w := 1
+	for _, h := range hunks {
+		w = max(w, len(fmt.Sprint(h.OldStart+h.OldLines)), len(fmt.Sprint(h.NewStart+h.NewLines)))
+	}
+	return w
+}
+
+// lineNumbers returns the old and new line-number gutter of each line of a
+// patch, blank where a side has no line.
+func lineNumbers(p string) []string {
+	hunks := patch.Parse(p)
+	w := numberWidth(hunks)
+	blank := strings.Repeat(" ", w)
+	out := make([]string, strings.Count(p, "\n")+1)
+	for i := range out {
+		out[i] = styleLineNum.Render(blank + " " + blank + " ")
+	}
+	num := func(n int) string {
+		if n == 0 {
+			return blank
+		}
+		return fmt.Sprintf("%*d", w, n)
+	}
+	for _, h := range hunks {
+		for _, l := range h.Lines[1:] {
+			if l.Kind != patch.NoNewline {
+				out[l.Index] = styleLineNum.Render(num(l.Old) + " " + num(l.New) + " ")
+			}
+		}
+	}
+	return out
+}
+
+func oldNum(l *patch.Line) int {
+	if l == nil {
+		return 0
+	}
+	return l.Old
+}
+
+func newNum(l *patch.Line) int {
+	if l == nil {
+		return 0
+	}
+	return l.New
+}
+
+// splitCell renders one side of a row, width cells wide: the line number,
+// the gutter and the line, cut to fit. colored holds the rendered patch
+// lines.
+func splitCell(l *patch.Line, num int, gutter string, colored []string, width, numW int) string {
+	if l == nil {
+		return strings.Repeat(" ", width)
+	}
+	// Drop the +, - or space the rendered line starts with.
+	text := ansi.TruncateLeft(colored[l.Index], 1, "")
+	w := max(width-numW-2, 1)
+	text = ansi.Truncate(text, w, "…")
+	pad := strings.Repeat(" ", max(w-ansi.StringWidth(text), 0))
+	return styleLineNum.Render(fmt.Sprintf("%*d ", numW, num)) + gutter + text + pad
+}
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kosuke9809/gh-review/highlight"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestRenderSplit(t *testing.T) {
+	h, err := highlight.New("monokai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := model.DiffFile{Filename: "main.go", Patch: "@@ -8,3 +8,3 @@\n a\n-b := \"日本語\"\n+b := \"日本語です\"\n+c\td\n d\n@@ -100 +100 @@\n-" + strings.Repeat("x", 200)}
+	for _, syntax := range []*highlight.Highlighter{nil, h} {
+		d := newDiffTab(160, 40)
+		d.split, d.syntax = true, syntax
+		if !d.splitActive() {
+			t.Fatalf("splitActive() = false at width %d", d.diffView.Width)
+		}
+		rows := strings.Split(ansi.Strip(d.renderPatch(f)), "\n")
+		want := []string{
+			"@@ -8,3 +8,3 @@",
+			"  8  a",
+			"  9  b := \"日本語\"",
+			" 10  d",
+			"@@ -100 +100 @@",
+			"100  xxx",
+		}
+		if len(rows) != 7 {
+			t.Fatalf("rows = %q, want 7", rows)
+		}
+		for i, w := range want {
+			if r := []int{0, 1, 2, 4, 5, 6}[i]; !strings.HasPrefix(rows[r], w) {
+				t.Errorf("row %d = %q, want prefix %q", r, rows[r], w)
+			}
+		}
+		colW := (d.diffView.Width - 1) / 2
+		for _, r := range []int{1, 2, 3, 4, 6} {
+			if got := ansi.StringWidth(rows[r]); got != 2*colW+1 {
+				t.Errorf("row %d is %d cells wide, want %d: %q", r, got, 2*colW+1, rows[r])
+			}
+			sep := strings.Index(rows[r], "│")
+			if sep < 0 {
+				t.Errorf("row %d has no separator: %q", r, rows[r])
+				continue
+			}
+			if strings.Contains(rows[r], "\t") || ansi.StringWidth(rows[r][:sep]) != colW {
+				t.Errorf("row %d has the separator at column %d, want %d: %q", r, ansi.StringWidth(rows[r][:sep]), colW, rows[r])
+			}
+		}
+		if right := rows[3][strings.Index(rows[3], "│")+len("│"):]; !strings.HasPrefix(strings.TrimLeft(right, " "), "10") || !strings.Contains(right, "c   d") || strings.TrimSpace(rows[3][:strings.Index(rows[3], "│")]) != "" {
+			t.Errorf("added line row = %q, want it on the new side only, its tab up to the next stop", rows[3])
+		}
+		if !strings.HasSuffix(rows[6], "…│"+strings.Repeat(" ", colW)) {
+			t.Errorf("long deleted line = %q, want it cut with an empty new side", rows[6])
+		}
+	}
+}
+
+func TestRenderSplit_Narrow(t *testing.T) {
+	d := newDiffTab(100, 40)
+	d.split = true
+	f := model.DiffFile{Filename: "a.go", Patch: "@@ -1 +1 @@\n-a\n+b"}
+	if d.splitActive() {
+		t.Fatalf("splitActive() = true at width %d", d.diffView.Width)
+	}
+	if got, want := ansi.Strip(d.renderPatch(f)), "    @@ -1 +1 @@\n1   -a\n  1 +b"; got != want {
+		t.Errorf("renderPatch() = %q, want the unified patch %q", got, want)
+	}
+}
@@ -0,0 +1,182 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+// statsPeriods are the selectable Stats periods, in days.
+var statsPeriods = []int{7, 30, 90}
+
+const statsBarWidth = 30
+
+type statsTabModel struct {
+	viewport viewport.Model
+	stats    *model.ReviewStats
+	width    int
+	height   int
+}
+
+func newStatsTab(width, height int) statsTabModel {
+	vp := viewport.New(width, height-4)
+	return statsTabModel{viewport: vp, width: width, height: height}
+}
+
+func (m statsTabModel) SetStats(st *model.ReviewStats) statsTabModel {
+	m.stats = st
+	if st != nil {
+		m.viewport.SetContent(RenderStatsContent(*st))
+	}
+	return m
+}
+
+// RenderStatsContent builds the text content for the Stats screen.
+func RenderStatsContent(st model.ReviewStats) string {
+	var b strings.Builder
+	sep := lipgloss.NewStyle().Foreground(colorGray).Render(strings.Repeat("─", 60))
+	bold := lipgloss.NewStyle().Bold(true)
+
+	b.WriteString(bold.Render(fmt.Sprintf("Reviews since %s", st.Since.Format("2006-01-02"))))
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("  PRs reviewed                %d\n", st.Reviewed))
+	b.WriteString(fmt.Sprintf("  Current queue depth         %d\n", st.QueueDepth))
+	first := "n/a"
+	if st.FirstReviewSamples > 0 {
+		first = fmt.Sprintf("%s (%d PRs)", formatDuration(st.MedianFirstReview), st.FirstReviewSamples)
+	}
+	b.WriteString(fmt.Sprintf("  Median time to first review %s\n", first))
+
+	b.WriteString("\n" + sep + "\n")
+	b.WriteString(bold.Render("Verdicts"))
+	b.WriteString("\n")
+	verdicts := st.Approved + st.ChangesRequested
+	maxVerdict := max(st.Approved, st.ChangesRequested, st.Commented)
+	for _, v := range []struct {
+		label string
+		n     int
+		style lipgloss.Style
+	}{
+		{"Approved", st.Approved, styleCIPass},
+		{"Changes requested", st.ChangesRequested, styleCIFail},
+		{"Commented", st.Commented, lipgloss.NewStyle()},
+	} {
+		ratio := ""
+		if v.label != "Commented" && verdicts > 0 {
+			ratio = fmt.Sprintf(" (%d%%)", v.n*100/verdicts)
+		}
+		b.WriteString(fmt.Sprintf("  %-18s %s %d%s\n", v.label, v.style.Render(textBar(v.n, maxVerdict, statsBarWidth)), v.n, ratio))
+	}
+
+	b.WriteString("\n" + sep + "\n")
+	b.WriteString(bold.Render("Lines reviewed per week"))
+	b.WriteString("\n")
+	maxLines := 0
+	for _, w := range st.LinesPerWeek {
+		maxLines = max(maxLines, w.Lines)
+	}
+	for _, w := range st.LinesPerWeek {
+		b.WriteString(fmt.Sprintf("  %s  %s %d\n", w.Start.Format("01-02"),
+			styleDiffHdr.Render(textBar(w.Lines, maxLines, statsBarWidth)), w.Lines))
+	}
+	return b.String()
+}
+
+// textBar renders n/max as a left-aligned bar of at most width cells.
+func textBar(n, maxN, width int) string {
+	filled := 0
+	if maxN > 0 {
+		filled = n * width / maxN
+	}
+	if n > 0 && filled == 0 {
+		filled = 1
+	}
+	return strings.Repeat("█", filled) + strings.Repeat(" ", width-filled)
+}
+
+func (m statsTabModel) Update(msg tea.Msg) (statsTabModel, tea.Cmd) {
+	var cmd tea.Cmd
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "j":
+			m.viewport.ScrollDown(1)
+			return m, nil
+		case "k":
+			m.viewport.ScrollUp(1)
+			return m, nil
+		}
+	}
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m statsTabModel) View() string {
+	if m.stats == nil {
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height-4).
+			Align(lipgloss.Center, lipgloss.Center).
+			Render("No review statistics yet")
+	}
+	return m.viewport.View()
+}
+
+// loadStats shows the Stats for the selected period at once when earlier
+// fetches cover it, and fetches the PRs reviewed in it that changed since.
+func (m AppModel) loadStats() (AppModel, tea.Cmd) {
+	since := startOfDay(time.Now()).AddDate(0, 0, -statsPeriods[m.statsPeriod])
+	m.loadingStats = m.statsFrom.IsZero() || since.Before(m.statsFrom)
+	if !m.loadingStats {
+		m = m.showStats(since)
+	}
+	return m, m.statsFetchCmd(since)
+}
+
+// applyStats caches fetched activities, and shows them when they are for the
+// selected period.
+func (m AppModel) applyStats(msg statsFetchedMsg) AppModel {
+	current := msg.since.Equal(startOfDay(time.Now()).AddDate(0, 0, -statsPeriods[m.statsPeriod]))
+	if current {
+		m.loadingStats = false
+	}
+	if msg.err != nil {
+		m.err = msg.err
+		return m
+	}
+	for _, a := range msg.activities {
+		m.statsCache[a.Number] = a
+	}
+	if m.statsFrom.IsZero() || msg.since.Before(m.statsFrom) {
+		m.statsFrom = msg.since
+	}
+	if current {
+		m = m.showStats(msg.since)
+	}
+	return m
+}
+
+// showStats computes the Stats since the given time from the cached
+// activities.
+func (m AppModel) showStats(since time.Time) AppModel {
+	queue := 0
+	now := time.Now()
+	for _, pr := range m.allPRs {
+		if pr = m.annotate(pr, now); pr.IsReviewRequested && pr.Snooze == nil {
+			queue++
+		}
+	}
+	acts := slices.SortedFunc(maps.Values(m.statsCache), func(a, b model.ReviewActivity) int {
+		return cmp.Compare(a.Number, b.Number)
+	})
+	st := model.ComputeReviewStats(acts, m.state.ReviewHistory, m.repoName, queue, since, now)
+	m.statsTab = m.statsTab.SetStats(&st)
+	return m
+}
@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestLoadStats_FromCache(t *testing.T) {
+	m := AppModel{statsTab: newStatsTab(80, 20), statsCache: make(map[int]model.ReviewActivity), statsPeriod: 2}
+	m, cmd := m.loadStats()
+	if !m.loadingStats || cmd == nil {
+		t.Fatal("first load not fetching")
+	}
+	since := startOfDay(time.Now()).AddDate(0, 0, -statsPeriods[2])
+	reviewed := time.Now().Add(-time.Hour)
+	acts := []model.ReviewActivity{{Number: 3, Reviews: []model.Review{{Author: "me", State: "APPROVED", CreatedAt: reviewed}}}}
+
+	// A reply for another period is cached but not shown.
+	m = m.applyStats(statsFetchedMsg{since: since.AddDate(0, 0, 1), activities: acts})
+	if !m.loadingStats || m.statsTab.stats != nil {
+		t.Error("stats of another period shown")
+	}
+	m = m.applyStats(statsFetchedMsg{since: since, activities: acts})
+	if m.loadingStats || m.statsTab.stats == nil || m.statsTab.stats.Reviewed != 1 {
+		t.Fatalf("stats = %+v, want 1 PR reviewed", m.statsTab.stats)
+	}
+
+	// A shorter period is covered by the cache and shown at once.
+	m.statsPeriod, m.statsTab = 0, newStatsTab(80, 20)
+	m, cmd = m.loadStats()
+	if m.loadingStats || m.statsTab.stats == nil || cmd == nil {
+		t.Errorf("loadingStats = %v, stats = %+v; want cached stats shown while refreshing", m.loadingStats, m.statsTab.stats)
+	}
+}
@@ -1,7 +1,11 @@
 package tui
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/charmbracelet/lipgloss"
+	"github.com/kosuke9809/gh-review/git"
 	"github.com/kosuke9809/gh-review/model"
 )
 
@@ -39,6 +43,13 @@ var (
 	styleDiffDel = lipgloss.NewStyle().Foreground(colorRed)
 	styleDiffHdr = lipgloss.NewStyle().Foreground(colorCyan)
 
+	// Backgrounds of added and deleted lines under syntax highlighting, and
+	// of their changed words.
+	colorDiffAddBg     = lipgloss.CompleteColor{TrueColor: "#1e3a26", ANSI256: "22", ANSI: "0"}
+	colorDiffDelBg     = lipgloss.CompleteColor{TrueColor: "#4a1f24", ANSI256: "52", ANSI: "0"}
+	colorDiffAddWordBg = lipgloss.CompleteColor{TrueColor: "#2f6b3d", ANSI256: "28", ANSI: "8"}
+	colorDiffDelWordBg = lipgloss.CompleteColor{TrueColor: "#8c2f3a", ANSI256: "88", ANSI: "8"}
+
 	styleBorder = lipgloss.NewStyle().
 			Border(lipgloss.NormalBorder()).
 			BorderForeground(colorGreen)
@@ -51,6 +62,13 @@ var (
 	styleRowUpd  = lipgloss.NewStyle().Foreground(colorYellow)
 	styleRowDone = lipgloss.NewStyle().Foreground(colorGray)
 	styleRowChg  = lipgloss.NewStyle().Foreground(colorRed)
+
+	styleSLAWarning = lipgloss.NewStyle().Foreground(colorYellow).Bold(true)
+	styleSLAOverdue = lipgloss.NewStyle().Foreground(colorRed).Bold(true)
+
+	styleSizeSmall = lipgloss.NewStyle().Foreground(colorGreen)
+	styleSizeMid   = lipgloss.NewStyle().Foreground(colorYellow)
+	styleSizeLarge = lipgloss.NewStyle().Foreground(colorRed)
 )
 
 func badgeForState(state string) string {
@@ -67,6 +85,21 @@ func badgeForState(state string) string {
 	return ""
 }
 
+func sizeBadge(size model.SizeClass, estimate time.Duration) string {
+	label := string(size)
+	if estimate > 0 {
+		label += " ~" + formatDuration(estimate.Round(time.Minute))
+	}
+	style := styleSizeMid
+	switch size {
+	case model.SizeXS, model.SizeS:
+		style = styleSizeSmall
+	case model.SizeXL, model.SizeXXL:
+		style = styleSizeLarge
+	}
+	return style.Render("[" + label + "]")
+}
+
 func ciIconStr(s string) string {
 	switch s {
 	case "pass":
@@ -79,6 +112,34 @@ func ciIconStr(s string) string {
 	return "?"
 }
 
+// rowStyleForPR colors a PR title by its SLA level when the review is
+// running late, and by its ReviewState otherwise.
+func rowStyleForPR(pr model.PR) lipgloss.Style {
+	switch pr.SLA {
+	case model.SLAOverdue:
+		return styleSLAOverdue
+	case model.SLAWarning:
+		return styleSLAWarning
+	}
+	return rowStyleForState(pr.ReviewState)
+}
+
+// waitingStr shows how long pr has waited on me, in working days of length
+// day.
+func waitingStr(pr model.PR, day time.Duration) string {
+	if pr.SLA == model.SLANone {
+		return ""
+	}
+	age := "⏱ " + model.FormatWorkingTime(pr.Waiting, day)
+	switch pr.SLA {
+	case model.SLAOverdue:
+		return styleSLAOverdue.Render(age)
+	case model.SLAWarning:
+		return styleSLAWarning.Render(age)
+	}
+	return age
+}
+
 func rowStyleForState(state model.ReviewState) lipgloss.Style {
 	switch state {
 	case model.ReviewStateNew:
@@ -92,3 +153,71 @@ func rowStyleForState(state model.ReviewState) lipgloss.Style {
 	}
 	return lipgloss.NewStyle()
 }
+
+// trackingStr describes the push state of a branch worktree.
+func trackingStr(t model.TrackingStatus) string {
+	if t.Upstream == "" {
+		return styleUnread.Render(fmt.Sprintf("Branch %s has no upstream; git push will not update the PR", t.Branch))
+	}
+	s := fmt.Sprintf("Branch %s → %s", t.Branch, t.Upstream)
+	switch {
+	case t.Ahead > 0 && t.Behind > 0:
+		return s + "  " + styleCIFail.Render(fmt.Sprintf("diverged: %d to push, %d to pull", t.Ahead, t.Behind))
+	case t.Ahead > 0:
+		return s + "  " + styleUnread.Render(fmt.Sprintf("↑%d to push", t.Ahead))
+	case t.Behind > 0:
+		return s + "  " + styleUnread.Render(fmt.Sprintf("↓%d to pull", t.Behind))
+	}
+	return s + "  " + styleCIPass.Render("up to date")
+}
+
+// hookBadge marks a worktree whose hooks are running or failed.
+func hookBadge(s model.HookStatus) string {
+	switch s {
+	case model.HookRunning:
+		return " " + styleCIPending.Render("hook…")
+	case model.HookFailed:
+		return " " + styleCIFail.Render("hook✗")
+	}
+	return ""
+}
+
+// localRunBadge shows the outcome of the last command run in pr's worktree,
+// dimmed when it ran against an older commit.
+func localRunBadge(pr model.PR) string {
+	r := pr.LocalRun
+	if r == nil {
+		return ""
+	}
+	icon := "✓"
+	style := styleCIPass
+	switch {
+	case r.Running:
+		icon, style = "●", styleCIPending
+	case !r.Passed():
+		icon, style = "✗", styleCIFail
+	}
+	if r.Stale(pr) {
+		style = lipgloss.NewStyle().Foreground(colorGray)
+	}
+	return "  Local:" + style.Render(icon)
+}
+
+// localRunStr describes the last command run in pr's worktree.
+func localRunStr(pr model.PR) string {
+	r := *pr.LocalRun
+	var s string
+	switch {
+	case r.Running:
+		return styleCIPending.Render("Running " + r.Name)
+	case r.Passed():
+		s = styleCIPass.Render("Last run: " + r.Name + " passed")
+	default:
+		s = styleCIFail.Render(fmt.Sprintf("Last run: %s failed (exit %d)", r.Name, r.ExitCode))
+	}
+	s += " " + r.At.Format("01-02 15:04")
+	if r.Stale(pr) {
+		s += "  " + styleUnread.Render("at "+git.ShortSHA(r.HeadSHA)+", not the PR head")
+	}
+	return s
+}
@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/kosuke9809/gh-review/git"
+	"github.com/kosuke9809/gh-review/model"
+)
+
+func TestWithWorktrees(t *testing.T) {
+	prs := []model.PR{{Number: 1}, {Number: 12}, {Number: 3}}
+	wts := map[int]git.Worktree{
+		12: {Path: "/wt/pr-12", HEAD: "abc", Detached: true},
+		3:  {Path: "/wt/pr-3", HEAD: "def", Branch: "fix"},
+	}
+	var looked []string
+	status := func(path string) *model.TrackingStatus {
+		looked = append(looked, path)
+		return &model.TrackingStatus{Branch: "fix", Ahead: 1}
+	}
+
+	got := withWorktrees(prs, wts, status)
+	if got[0].HasWorktree {
+		t.Errorf("#1 HasWorktree = true, want false")
+	}
+	if !got[1].HasWorktree || got[1].WorktreePath != "/wt/pr-12" || got[1].WorktreeHEAD != "abc" || got[1].WorktreeBranch != nil {
+		t.Errorf("#12 = %+v", got[1])
+	}
+	if got[2].WorktreeBranch == nil || got[2].WorktreeBranch.Ahead != 1 {
+		t.Errorf("#3 WorktreeBranch = %+v, want push status", got[2].WorktreeBranch)
+	}
+	if len(looked) != 1 || looked[0] != "/wt/pr-3" {
+		t.Errorf("status looked up for %v, want only the branch worktree", looked)
+	}
+}
+
+func TestWithFiles(t *testing.T) {
+	files := []model.DiffFile{{Filename: "a.go", Additions: 3}}
+	prev := []model.PR{
+		{Number: 1, HeadSHA: "aaa", DetailLoaded: true, FilesLoaded: true, DiffFiles: files, CIStatus: model.CIStatusPending},
+		{Number: 2, HeadSHA: "bbb", DetailLoaded: true, FilesLoaded: true, DiffFiles: files},
+		{Number: 3, HeadSHA: "ccc"},
+	}
+	prs := []model.PR{{Number: 1, HeadSHA: "aaa"}, {Number: 2, HeadSHA: "bbb2"}, {Number: 3, HeadSHA: "ccc"}}
+
+	got := withFiles(prs, prev)
+	if !got[0].FilesLoaded || got[0].ChangedLines() != 3 {
+		t.Errorf("#1 = %+v, want files kept", got[0])
+	}
+	if got[0].DetailLoaded || got[0].CIStatus != "" {
+		t.Errorf("#1 DetailLoaded = %v, CIStatus = %q; want CI and reviews fetched again", got[0].DetailLoaded, got[0].CIStatus)
+	}
+	if got[1].FilesLoaded || got[1].DiffFiles != nil {
+		t.Errorf("#2 = %+v, want files dropped after a push", got[1])
+	}
+	if got[2].FilesLoaded {
+		t.Errorf("#3 FilesLoaded = true, want false")
+	}
+}
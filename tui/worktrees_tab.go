@@ -0,0 +1,254 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kosuke9809/gh-review/git"
+)
+
+// worktreeRow is a PR worktree together with the state of its PR.
+type worktreeRow struct {
+	git.WorktreeStatus
+	PRState  string // "open", "closed", "merged", or "" when unknown
+	Title    string
+	Outdated bool   // HEAD differs from the open PR's head
+	Target   string // path under the configured layout; empty if unknown
+}
+
+// misplaced reports whether the worktree is not where the configured layout
+// places it.
+func (r worktreeRow) misplaced() bool {
+	return r.Target != "" && r.Target != r.Path
+}
+
+// closed reports whether the row's PR is no longer open.
+func (r worktreeRow) closed() bool {
+	return r.PRState == "closed" || r.PRState == "merged"
+}
+
+type worktreeItem struct {
+	row    worktreeRow
+	marked bool
+}
+
+func (w worktreeItem) Title() string {
+	name := fmt.Sprintf("#%d", w.row.PRNumber)
+	if w.row.PRNumber == 0 {
+		name = w.row.Path
+	}
+	if w.row.Title != "" {
+		name += "  " + w.row.Title
+	}
+	return name
+}
+
+func (w worktreeItem) Description() string {
+	ref := "detached@" + git.ShortSHA(w.row.HEAD)
+	if w.row.Branch != "" {
+		ref = w.row.Branch
+	}
+	used := "-"
+	if !w.row.LastUsed.IsZero() {
+		used = formatDuration(time.Since(w.row.LastUsed)) + " ago"
+	}
+	return fmt.Sprintf("%s  %s  used %s  %s", ref, formatBytes(w.row.DiskUsage), used, w.row.Path)
+}
+
+func (w worktreeItem) FilterValue() string { return w.row.Path }
+
+func (w worktreeItem) badges() string {
+	state := w.row.PRState
+	if state == "" {
+		state = "unknown"
+	}
+	style := lipgloss.NewStyle().Foreground(colorGray)
+	switch state {
+	case "open":
+		style = styleCIPass
+	case "closed", "merged":
+		style = styleCIFail
+	}
+	s := style.Render("[" + state + "]")
+	if w.row.Dirty {
+		s += " " + styleUnread.Render("[dirty]")
+	}
+	if w.row.Locked {
+		s += " " + lipgloss.NewStyle().Foreground(colorGray).Render("[locked]")
+	}
+	if w.row.Outdated {
+		s += " " + styleUnread.Render("[outdated]")
+	}
+	if w.row.misplaced() {
+		s += " " + lipgloss.NewStyle().Foreground(colorCyan).Render("[→ "+w.row.Target+"]")
+	}
+	return s
+}
+
+type worktreeItemDelegate struct {
+	list.DefaultDelegate
+}
+
+func (d worktreeItemDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	wt, ok := item.(worktreeItem)
+	if !ok {
+		d.DefaultDelegate.Render(w, m, index, item)
+		return
+	}
+	mark := "  "
+	if wt.marked {
+		mark = styleCIPass.Render("● ")
+	}
+	title := mark + wt.Title() + "  " + wt.badges()
+	desc := "  " + lipgloss.NewStyle().Foreground(colorGray).Render(wt.Description())
+	if index == m.Index() {
+		title = styleSelected.Render(mark+wt.Title()) + "  " + wt.badges()
+		desc = styleSelected.Foreground(colorGray).Render("  " + wt.Description())
+	}
+	fmt.Fprintf(w, "%s\n%s\n", title, desc)
+}
+
+func (d worktreeItemDelegate) Height() int  { return 2 }
+func (d worktreeItemDelegate) Spacing() int { return 1 }
+
+type worktreesTabModel struct {
+	list   list.Model
+	rows   []worktreeRow
+	marked map[string]bool
+	status string
+	width  int
+	height int
+}
+
+func newWorktreesTab(width, height int) worktreesTabModel {
+	l := list.New(nil, worktreeItemDelegate{DefaultDelegate: list.NewDefaultDelegate()}, width, height-4)
+	l.Title = ""
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	return worktreesTabModel{list: l, marked: map[string]bool{}, width: width, height: height}
+}
+
+// SetRows replaces the list contents, keeping marks on rows that remain.
+func (m worktreesTabModel) SetRows(rows []worktreeRow) worktreesTabModel {
+	m.rows = rows
+	marked := make(map[string]bool)
+	for _, r := range rows {
+		if m.marked[r.Path] {
+			marked[r.Path] = true
+		}
+	}
+	m.marked = marked
+	return m.refreshItems()
+}
+
+func (m worktreesTabModel) refreshItems() worktreesTabModel {
+	items := make([]list.Item, len(m.rows))
+	for i, r := range m.rows {
+		items[i] = worktreeItem{row: r, marked: m.marked[r.Path]}
+	}
+	m.list.SetItems(items)
+	return m
+}
+
+func (m worktreesTabModel) Selected() *worktreeRow {
+	if item, ok := m.list.SelectedItem().(worktreeItem); ok {
+		return &item.row
+	}
+	return nil
+}
+
+// ToggleMark marks or unmarks the selected row for removal.
+func (m worktreesTabModel) ToggleMark() worktreesTabModel {
+	r := m.Selected()
+	if r == nil {
+		return m
+	}
+	marked := make(map[string]bool, len(m.marked)+1)
+	for k, v := range m.marked {
+		marked[k] = v
+	}
+	marked[r.Path] = !marked[r.Path]
+	m.marked = marked
+	return m.refreshItems()
+}
+
+// Targets returns the marked rows, or the selected row when none is marked.
+func (m worktreesTabModel) Targets() []worktreeRow {
+	var result []worktreeRow
+	for _, r := range m.rows {
+		if m.marked[r.Path] {
+			result = append(result, r)
+		}
+	}
+	if len(result) == 0 {
+		if r := m.Selected(); r != nil {
+			result = append(result, *r)
+		}
+	}
+	return result
+}
+
+// Misplaced returns the rows to move to the configured layout.
+func (m worktreesTabModel) Misplaced() []worktreeRow {
+	var result []worktreeRow
+	for _, r := range m.rows {
+		if r.misplaced() {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// Closed returns the rows whose PR is closed or merged.
+func (m worktreesTabModel) Closed() []worktreeRow {
+	var result []worktreeRow
+	for _, r := range m.rows {
+		if r.closed() {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+func (m worktreesTabModel) Update(msg tea.Msg) (worktreesTabModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m worktreesTabModel) View() string {
+	if len(m.rows) == 0 {
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(m.height-4).
+			Align(lipgloss.Center, lipgloss.Center).
+			Render("No PR worktrees")
+	}
+	return m.list.View()
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func removeTargets(rows []worktreeRow) []removeTarget {
+	targets := make([]removeTarget, len(rows))
+	for i, r := range rows {
+		targets[i] = removeTarget{prNumber: r.PRNumber, path: r.Path}
+	}
+	return targets
+}
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/kosuke9809/gh-review/git"
+)
+
+func wtRow(path, state string) worktreeRow {
+	return worktreeRow{WorktreeStatus: git.WorktreeStatus{Worktree: git.Worktree{Path: path}}, PRState: state}
+}
+
+func TestWorktreesTab_Targets(t *testing.T) {
+	m := newWorktreesTab(80, 40).SetRows([]worktreeRow{wtRow("/a", "open"), wtRow("/b", "merged"), wtRow("/c", "closed")})
+
+	if got := m.Targets(); len(got) != 1 || got[0].Path != "/a" {
+		t.Fatalf("Targets() without marks = %v, want selected /a", got)
+	}
+
+	m.list.Select(2)
+	m = m.ToggleMark()
+	m.list.Select(0)
+	m = m.ToggleMark()
+	got := m.Targets()
+	if len(got) != 2 || got[0].Path != "/a" || got[1].Path != "/c" {
+		t.Fatalf("Targets() = %v, want /a and /c", got)
+	}
+
+	m = m.SetRows([]worktreeRow{wtRow("/c", "closed")})
+	if got := m.Targets(); len(got) != 1 || got[0].Path != "/c" {
+		t.Fatalf("Targets() after reload = %v, want /c still marked", got)
+	}
+}
+
+func TestWorktreesTab_Closed(t *testing.T) {
+	m := newWorktreesTab(80, 40).SetRows([]worktreeRow{wtRow("/a", "open"), wtRow("/b", "merged"), wtRow("/c", "closed"), wtRow("/d", "")})
+	got := m.Closed()
+	if len(got) != 2 || got[0].Path != "/b" || got[1].Path != "/c" {
+		t.Fatalf("Closed() = %v, want /b and /c", got)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{512, "512 B"},
+		{2048, "2.0 KiB"},
+		{5 << 20, "5.0 MiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestWorktreesTab_Misplaced(t *testing.T) {
+	moved := wtRow("/repo/.worktrees/pr-1", "open")
+	moved.Target = "/wt/app/1-fix"
+	inPlace := wtRow("/wt/app/2-feat", "open")
+	inPlace.Target = "/wt/app/2-feat"
+	unknown := wtRow("/repo/.worktrees/pr-3", "closed")
+
+	got := newWorktreesTab(80, 40).SetRows([]worktreeRow{moved, inPlace, unknown}).Misplaced()
+	if len(got) != 1 || got[0].Path != moved.Path {
+		t.Fatalf("Misplaced() = %v, want only %s", got, moved.Path)
+	}
+}
@@ -0,0 +1,162 @@
+// Package worddiff finds the words that changed between the old and new
+// versions of a line, for emphasis within the lines of a patch.
+package worddiff
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kosuke9809/gh-review/patch"
+)
+
+// minSimilarity is the share of text two lines must have in common for
+// their changed words to be shown; less similar lines are rewrites.
+const minSimilarity = 0.5
+
+// maxCells bounds the size of the comparison, so very long lines are not
+// compared word by word.
+const maxCells = 250_000
+
+// Range is the byte range [Start, End) of a changed part of a line.
+type Range struct {
+	Start, End int
+}
+
+// Words returns the changed ranges of a and b, the old and new versions of a
+// line. ok is false when the lines share too little to be worth comparing.
+func Words(a, b string) (old, new []Range, ok bool) {
+	ta, tb := tokenize(a), tokenize(b)
+	if len(ta)*len(tb) > maxCells {
+		return nil, nil, false
+	}
+	keepA, keepB := lcs(ta, tb)
+	common := 0
+	for i, t := range ta {
+		if keepA[i] {
+			common += len(t)
+		}
+	}
+	if total := len(a) + len(b); total > 0 && float64(2*common)/float64(total) < minSimilarity {
+		return nil, nil, false
+	}
+	return changed(ta, keepA), changed(tb, keepB), true
+}
+
+// Patch returns the changed ranges of the paired deleted and added lines of
+// a unified diff patch, keyed by the index of the line in the patch. Ranges
+// are relative to the line's text, without its leading + or -.
+func Patch(p string) map[int][]Range {
+	out := make(map[int][]Range)
+	for _, h := range patch.Parse(p) {
+		for _, r := range patch.Split(h) {
+			if r.Old == nil || r.New == nil || r.Old.Kind != patch.Deleted {
+				continue
+			}
+			old, new, ok := Words(r.Old.Text, r.New.Text)
+			if !ok {
+				continue
+			}
+			out[r.Old.Index], out[r.New.Index] = old, new
+		}
+	}
+	return out
+}
+
+// tokenize splits s into words, runs of spaces, and single other characters.
+func tokenize(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		j := i + size
+		if class := runeClass(r); class != 0 {
+			for j < len(s) {
+				r, size := utf8.DecodeRuneInString(s[j:])
+				if runeClass(r) != class {
+					break
+				}
+				j += size
+			}
+		}
+		tokens = append(tokens, s[i:j])
+		i = j
+	}
+	return tokens
+}
+
+// runeClass groups the runes that form a token together: 1 for words, 2 for
+// spaces, and 0 for anything else, which stands alone.
+func runeClass(r rune) int {
+	switch {
+	case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		return 1
+	case unicode.IsSpace(r):
+		return 2
+	}
+	return 0
+}
+
+// lcs marks the tokens of a and b that are part of a longest common
+// subsequence.
+func lcs(a, b []string) (keepA, keepB []bool) {
+	// n[i][j] is the LCS length of a[i:] and b[j:].
+	n := make([][]int, len(a)+1)
+	for i := range n {
+		n[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				n[i][j] = n[i+1][j+1] + 1
+			} else {
+				n[i][j] = max(n[i+1][j], n[i][j+1])
+			}
+		}
+	}
+	keepA, keepB = make([]bool, len(a)), make([]bool, len(b))
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			keepA[i], keepB[j] = true, true
+			i++
+			j++
+		case n[i+1][j] >= n[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return keepA, keepB
+}
+
+// changed returns the byte ranges of the tokens not kept. Ranges separated
+// only by spaces are merged.
+func changed(tokens []string, keep []bool) []Range {
+	var out []Range
+	off := 0
+	for i, t := range tokens {
+		start := off
+		off += len(t)
+		if keep[i] {
+			continue
+		}
+		if n := len(out); n > 0 && spacesOnly(tokens, keep, out[n-1].End, start) {
+			out[n-1].End = off
+			continue
+		}
+		out = append(out, Range{Start: start, End: off})
+	}
+	return out
+}
+
+// spacesOnly reports whether the kept tokens between byte offsets from and
+// to are all spaces.
+func spacesOnly(tokens []string, keep []bool, from, to int) bool {
+	off := 0
+	for i, t := range tokens {
+		if off >= from && off < to && (!keep[i] || runeClass([]rune(t)[0]) != 2) {
+			return false
+		}
+		off += len(t)
+	}
+	return true
+}
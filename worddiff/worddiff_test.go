@@ -0,0 +1,59 @@
+package worddiff_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kosuke9809/gh-review/worddiff"
+)
+
+func cut(s string, ranges []worddiff.Range) []string {
+	var out []string
+	for _, r := range ranges {
+		out = append(out, s[r.Start:r.End])
+	}
+	return out
+}
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		wantOld []string
+		wantNew []string
+		wantOK  bool
+	}{
+		{"識別子の変更", "x := oldName(a, b)", "x := newName(a, b)", []string{"oldName"}, []string{"newName"}, true},
+		{"引数の追加", "f(a)", "f(a, b)", nil, []string{", b"}, true},
+		{"空白を挟んだ変更はまとめる", "return a + b", "return c - d", []string{"a + b"}, []string{"c - d"}, true},
+		{"マルチバイト", "log.Printf(\"%s: こんにちは\", name)", "log.Printf(\"%s: さようなら\", name)", []string{"こんにちは"}, []string{"さようなら"}, true},
+		{"同じ行", "same", "same", nil, nil, true},
+		{"書き換え", "for i := range xs {", "return nil", nil, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, new, ok := worddiff.Words(tt.a, tt.b)
+			if ok != tt.wantOK {
+				t.Fatalf("Words() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got := cut(tt.a, old); !reflect.DeepEqual(got, tt.wantOld) {
+				t.Errorf("old = %q, want %q", got, tt.wantOld)
+			}
+			if got := cut(tt.b, new); !reflect.DeepEqual(got, tt.wantNew) {
+				t.Errorf("new = %q, want %q", got, tt.wantNew)
+			}
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	p := "@@ -1,3 +1,3 @@\n a := 1\n-b := old(a)\n+b := new(a)\n+added := true\n c := 3"
+	got := worddiff.Patch(p)
+	want := map[int][]worddiff.Range{
+		2: {{Start: 5, End: 8}},
+		3: {{Start: 5, End: 8}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Patch() = %v, want %v", got, want)
+	}
+}